@@ -0,0 +1,277 @@
+package cache
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	cl "github.com/nl253/DataStructures/list"
+)
+
+type Reason uint8
+
+const (
+	Evicted Reason = iota
+	Expired
+)
+
+func (r Reason) String() string {
+	if r == Expired {
+		return "expired"
+	}
+	return "evicted"
+}
+
+type Options struct {
+	Policy  Policy
+	MaxSize uint
+	MaxCost uint64
+	TTL     time.Duration
+	OnEvict func(key interface{}, val interface{}, reason Reason)
+}
+
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+}
+
+func (s Stats) HitRatio() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+type Cache struct {
+	lk     *sync.Mutex
+	opts   Options
+	items  map[interface{}]*entry
+	policy policy
+	cost   uint64
+	stats  Stats
+	now    func() time.Time
+}
+
+type entry struct {
+	key      interface{}
+	val      interface{}
+	cost     uint64
+	expires  time.Time
+	elem     *list.Element
+	idx      int
+	freq     uint64
+	tick     uint64
+	frequent bool
+}
+
+type evicted struct {
+	e      *entry
+	reason Reason
+}
+
+func New(opts Options) *Cache {
+	return &Cache{
+		lk:     &sync.Mutex{},
+		opts:   opts,
+		items:  make(map[interface{}]*entry),
+		policy: newPolicy(opts.Policy, opts.MaxSize),
+		now:    time.Now,
+	}
+}
+
+func NewLRU(size uint) *Cache { return New(Options{Policy: LRU, MaxSize: size}) }
+
+func NewLFU(size uint) *Cache { return New(Options{Policy: LFU, MaxSize: size}) }
+
+func NewARC(size uint) *Cache { return New(Options{Policy: ARC, MaxSize: size}) }
+
+func (c *Cache) Set(key interface{}, val interface{}) {
+	c.SetWithCostTTL(key, val, 1, c.opts.TTL)
+}
+
+func (c *Cache) SetWithTTL(key interface{}, val interface{}, ttl time.Duration) {
+	c.SetWithCostTTL(key, val, 1, ttl)
+}
+
+func (c *Cache) SetWithCost(key interface{}, val interface{}, cost uint64) {
+	c.SetWithCostTTL(key, val, cost, c.opts.TTL)
+}
+
+// SetWithCostTTL inserts or replaces key. A ttl of 0 means the entry never
+// expires. Entries are evicted until both MaxSize and MaxCost are respected.
+func (c *Cache) SetWithCostTTL(key interface{}, val interface{}, cost uint64, ttl time.Duration) {
+	var expires time.Time
+	c.lk.Lock()
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+	var dropped []evicted
+	if e, ok := c.items[key]; ok {
+		c.cost = c.cost - e.cost + cost
+		e.val = val
+		e.cost = cost
+		e.expires = expires
+		c.policy.hit(e)
+	} else {
+		c.policy.admit(key)
+		dropped = c.enforce(1, cost)
+		e = &entry{key: key, val: val, cost: cost, expires: expires}
+		c.items[key] = e
+		c.cost += cost
+		c.policy.insert(e)
+	}
+	dropped = append(dropped, c.enforce(0, 0)...)
+	c.lk.Unlock()
+	c.notify(dropped)
+}
+
+func (c *Cache) Get(key interface{}) (interface{}, bool) {
+	c.lk.Lock()
+	e, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		c.lk.Unlock()
+		return nil, false
+	}
+	if c.expired(e) {
+		c.drop(e)
+		c.stats.Misses++
+		c.stats.Expirations++
+		c.lk.Unlock()
+		c.notify([]evicted{{e, Expired}})
+		return nil, false
+	}
+	c.stats.Hits++
+	c.policy.hit(e)
+	val := e.val
+	c.lk.Unlock()
+	return val, true
+}
+
+// Peek looks key up without touching recency, frequency or statistics.
+func (c *Cache) Peek(key interface{}) (interface{}, bool) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if e, ok := c.items[key]; ok && !c.expired(e) {
+		return e.val, true
+	}
+	return nil, false
+}
+
+func (c *Cache) Contains(key interface{}) bool {
+	_, ok := c.Peek(key)
+	return ok
+}
+
+func (c *Cache) Remove(key interface{}) bool {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	e, ok := c.items[key]
+	if ok {
+		c.drop(e)
+	}
+	return ok
+}
+
+// Purge removes all expired entries and returns how many there were.
+func (c *Cache) Purge() uint {
+	c.lk.Lock()
+	dropped := make([]evicted, 0)
+	for _, e := range c.items {
+		if c.expired(e) {
+			c.drop(e)
+			c.stats.Expirations++
+			dropped = append(dropped, evicted{e, Expired})
+		}
+	}
+	c.lk.Unlock()
+	c.notify(dropped)
+	return uint(len(dropped))
+}
+
+func (c *Cache) Clear() {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	c.items = make(map[interface{}]*entry)
+	c.policy.clear()
+	c.cost = 0
+}
+
+func (c *Cache) Keys() *cl.ConcurrentList {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	keys := cl.New()
+	for k, e := range c.items {
+		if !c.expired(e) {
+			keys.PushBack(k)
+		}
+	}
+	return keys
+}
+
+func (c *Cache) Size() uint {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return uint(len(c.items))
+}
+
+func (c *Cache) Cost() uint64 {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.cost
+}
+
+func (c *Cache) Stats() Stats {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.stats
+}
+
+func (c *Cache) ResetStats() {
+	c.lk.Lock()
+	c.stats = Stats{}
+	c.lk.Unlock()
+}
+
+func (c *Cache) String() string {
+	s := c.Stats()
+	return fmt.Sprintf("Cache(%s, size=%d, cost=%d, hits=%d, misses=%d)", c.opts.Policy, c.Size(), c.Cost(), s.Hits, s.Misses)
+}
+
+func (c *Cache) expired(e *entry) bool {
+	return !e.expires.IsZero() && !c.now().Before(e.expires)
+}
+
+func (c *Cache) drop(e *entry) {
+	c.policy.remove(e)
+	delete(c.items, e.key)
+	c.cost -= e.cost
+}
+
+// enforce evicts until there is room for n more entries costing cost.
+func (c *Cache) enforce(n uint, cost uint64) []evicted {
+	dropped := make([]evicted, 0)
+	for len(c.items) > 0 && ((c.opts.MaxSize > 0 && uint(len(c.items))+n > c.opts.MaxSize) || (c.opts.MaxCost > 0 && c.cost+cost > c.opts.MaxCost)) {
+		e := c.policy.victim()
+		if e == nil {
+			break
+		}
+		delete(c.items, e.key)
+		c.cost -= e.cost
+		c.stats.Evictions++
+		dropped = append(dropped, evicted{e, Evicted})
+	}
+	return dropped
+}
+
+func (c *Cache) notify(dropped []evicted) {
+	if c.opts.OnEvict == nil {
+		return
+	}
+	for _, d := range dropped {
+		c.opts.OnEvict(d.e.key, d.e.val, d.reason)
+	}
+}
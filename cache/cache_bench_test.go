@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+const (
+	N    uint = 1000
+	KEYS int  = 4000
+)
+
+// naiveLRU is the map plus ConcurrentList recency list this package replaces.
+type naiveLRU struct {
+	lk    *sync.Mutex
+	size  uint
+	items map[interface{}]interface{}
+	order *list.ConcurrentList
+}
+
+func newNaiveLRU(size uint) *naiveLRU {
+	return &naiveLRU{lk: &sync.Mutex{}, size: size, items: make(map[interface{}]interface{}), order: list.New()}
+}
+
+func (c *naiveLRU) Get(key interface{}) (interface{}, bool) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	val, ok := c.items[key]
+	if ok {
+		c.order.RemoveVal(key)
+		c.order.PushFront(key)
+	}
+	return val, ok
+}
+
+func (c *naiveLRU) Set(key interface{}, val interface{}) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if _, ok := c.items[key]; ok {
+		c.order.RemoveVal(key)
+	}
+	c.items[key] = val
+	c.order.PushFront(key)
+	if c.order.Size() > c.size {
+		last, _ := c.order.RemoveAt(c.order.Size() - 1)
+		delete(c.items, last)
+	}
+}
+
+type getSetter interface {
+	Get(key interface{}) (interface{}, bool)
+	Set(key interface{}, val interface{})
+}
+
+func bench(b *testing.B, c getSetter) {
+	keys := make([]int, 1024)
+	for i := range keys {
+		keys[i] = int(rand.ExpFloat64() * float64(KEYS) / 8)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		k := keys[i%len(keys)]
+		if _, ok := c.Get(k); !ok {
+			c.Set(k, k)
+		}
+	}
+}
+
+func BenchmarkNaive_ConcurrentList(b *testing.B) { bench(b, newNaiveLRU(N)) }
+
+func BenchmarkCache_LRU(b *testing.B) { bench(b, NewLRU(N)) }
+
+func BenchmarkCache_LFU(b *testing.B) { bench(b, NewLFU(N)) }
+
+func BenchmarkCache_ARC(b *testing.B) { bench(b, NewARC(N)) }
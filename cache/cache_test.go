@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ut "github.com/nl253/Testing"
+)
+
+var fCache = ut.Test("Cache")
+
+func fill(c *Cache, keys ...interface{}) *Cache {
+	for _, k := range keys {
+		c.Set(k, k)
+	}
+	return c
+}
+
+func has(c *Cache, keys ...interface{}) []bool {
+	result := make([]bool, len(keys))
+	for i, k := range keys {
+		result[i] = c.Contains(k)
+	}
+	return result
+}
+
+func TestCache_LRU(t *testing.T) {
+	should := fCache("LRU", t)
+	should("evict the least recently used key", []bool{false, true, true}, func() interface{} {
+		c := fill(NewLRU(2), 1, 2)
+		c.Get(1)
+		c.Set(3, 3)
+		return has(c, 2, 1, 3)
+	})
+	should("not exceed max size", uint(10), func() interface{} {
+		c := NewLRU(10)
+		for i := 0; i < 100; i++ {
+			c.Set(i, i)
+		}
+		return c.Size()
+	})
+}
+
+func TestCache_LFU(t *testing.T) {
+	should := fCache("LFU", t)
+	should("evict the least frequently used key", []bool{true, false, true}, func() interface{} {
+		c := fill(NewLFU(2), 1, 2)
+		c.Get(1)
+		c.Get(1)
+		c.Get(2)
+		c.Get(1)
+		c.Set(3, 3)
+		return has(c, 1, 2, 3)
+	})
+	should("break frequency ties by recency", []bool{false, true, true}, func() interface{} {
+		c := fill(NewLFU(2), 1, 2)
+		c.Set(3, 3)
+		return has(c, 1, 2, 3)
+	})
+}
+
+func TestCache_ARC(t *testing.T) {
+	should := fCache("ARC", t)
+	should("keep frequently used keys when scanned", []bool{true, true}, func() interface{} {
+		c := fill(NewARC(4), "a", "b")
+		c.Get("a")
+		c.Get("b")
+		for i := 0; i < 100; i++ {
+			c.Set(i, i)
+		}
+		return has(c, "a", "b")
+	})
+	should("not exceed max size", uint(4), func() interface{} {
+		c := NewARC(4)
+		for i := 0; i < 100; i++ {
+			c.Set(i%7, i)
+			c.Get(i % 3)
+		}
+		return c.Size()
+	})
+	should("readmit recently evicted keys as frequent", true, func() interface{} {
+		c := fill(NewARC(2), 1, 2, 3)
+		c.Set(1, 1)
+		return c.items[1].frequent
+	})
+	should("adapt to a b2 ghost hit before evicting", []bool{true, true, false}, func() interface{} {
+		// a is evicted from t2 into b2 by the second b, setting it again
+		// lowers the target for t1 so c goes rather than b
+		c := fill(NewARC(2), "a", "b", "c", "a", "b")
+		c.Set("a", "a")
+		return has(c, "a", "b", "c")
+	})
+}
+
+func TestCache_SetWithTTL(t *testing.T) {
+	should := fCache("SetWithTTL", t)
+	now := time.Unix(0, 0)
+	clock := func() time.Time { return now }
+	should("expire entries after their ttl", []interface{}{true, false}, func() interface{} {
+		c := NewLRU(10)
+		c.now = clock
+		c.SetWithTTL("k", "v", time.Minute)
+		_, before := c.Get("k")
+		now = now.Add(time.Minute)
+		_, after := c.Get("k")
+		return []interface{}{before, after}
+	})
+	should("use the default ttl from options", uint(1), func() interface{} {
+		c := New(Options{TTL: time.Second})
+		c.now = clock
+		c.Set(1, 1)
+		c.SetWithTTL(2, 2, 0)
+		now = now.Add(time.Second)
+		return c.Purge()
+	})
+}
+
+func TestCache_SetWithCost(t *testing.T) {
+	should := fCache("SetWithCost", t)
+	should("evict until under max cost", []interface{}{uint(2), uint64(9)}, func() interface{} {
+		c := New(Options{MaxCost: 10})
+		c.SetWithCost(1, 1, 4)
+		c.SetWithCost(2, 2, 4)
+		c.SetWithCost(3, 3, 5)
+		return []interface{}{c.Size(), c.Cost()}
+	})
+	should("track cost when replacing", uint64(3), func() interface{} {
+		c := New(Options{MaxCost: 10})
+		c.SetWithCost(1, 1, 4)
+		c.SetWithCost(1, 1, 3)
+		return c.Cost()
+	})
+}
+
+func TestCache_OnEvict(t *testing.T) {
+	should := fCache("OnEvict", t)
+	should("report evicted and expired entries", []interface{}{1, Evicted, 2, Expired}, func() interface{} {
+		now := time.Unix(0, 0)
+		got := make([]interface{}, 0)
+		c := New(Options{MaxSize: 1, OnEvict: func(k interface{}, _ interface{}, r Reason) {
+			got = append(got, k, r)
+		}})
+		c.now = func() time.Time { return now }
+		c.Set(1, 1)
+		c.SetWithTTL(2, 2, time.Second)
+		now = now.Add(time.Second)
+		c.Get(2)
+		return got
+	})
+}
+
+func TestCache_Stats(t *testing.T) {
+	should := fCache("Stats", t)
+	should("count hits, misses and evictions", Stats{Hits: 2, Misses: 1, Evictions: 1}, func() interface{} {
+		c := fill(NewLRU(2), 1, 2, 3)
+		c.Get(2)
+		c.Get(3)
+		c.Get(1)
+		return c.Stats()
+	})
+	should("compute hit ratio", 0.5, func() interface{} { return Stats{Hits: 1, Misses: 1}.HitRatio() })
+}
+
+func TestCache_IsThreadSafe(t *testing.T) {
+	should := fCache("general concurrency", t)
+	for _, p := range []Policy{LRU, LFU, ARC} {
+		should("stay within bounds under concurrent access to "+p.String(), true, func() interface{} {
+			c := New(Options{Policy: p, MaxSize: 50})
+			wg := sync.WaitGroup{}
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < 1000; i++ {
+						c.Set((g*i)%200, i)
+						c.Get(i % 100)
+						if i%10 == 0 {
+							c.Remove(i % 50)
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			return c.Size() <= 50 && c.Keys().Size() == c.Size()
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func init() {
+	codec.Register("cache.Cache", &Cache{})
+}
+
+// snapshot lists live entries in the order the policy rebuilds itself from,
+// with the time each has left to live, 0 meaning it never expires. P, B1 and
+// B2 are the adaptive target and ghost keys of ARC, oldest first.
+type snapshot struct {
+	Policy  Policy
+	MaxSize uint
+	MaxCost uint64
+	TTL     time.Duration
+	Entries []entrySnapshot
+	P       int
+	B1      []interface{}
+	B2      []interface{}
+}
+
+type entrySnapshot struct {
+	Key      interface{}
+	Val      interface{}
+	Cost     uint64
+	TTL      time.Duration
+	Freq     uint64
+	Frequent bool
+}
+
+type jsonSnapshot struct {
+	Policy  Policy              `json:"policy"`
+	MaxSize uint                `json:"maxSize"`
+	MaxCost uint64              `json:"maxCost"`
+	TTL     time.Duration       `json:"ttl"`
+	Entries []jsonEntrySnapshot `json:"entries"`
+	P       int                 `json:"p,omitempty"`
+	B1      []codec.Value       `json:"b1,omitempty"`
+	B2      []codec.Value       `json:"b2,omitempty"`
+}
+
+type jsonEntrySnapshot struct {
+	Key      codec.Value   `json:"key"`
+	Val      codec.Value   `json:"val"`
+	Cost     uint64        `json:"cost"`
+	TTL      time.Duration `json:"ttl,omitempty"`
+	Freq     uint64        `json:"freq,omitempty"`
+	Frequent bool          `json:"frequent,omitempty"`
+}
+
+func (c *Cache) snapshot() snapshot {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	s := snapshot{
+		Policy:  c.opts.Policy,
+		MaxSize: c.opts.MaxSize,
+		MaxCost: c.opts.MaxCost,
+		TTL:     c.opts.TTL,
+		Entries: make([]entrySnapshot, 0, len(c.items)),
+	}
+	now := c.now()
+	for _, e := range c.policy.entries() {
+		if c.expired(e) {
+			continue
+		}
+		var ttl time.Duration
+		if !e.expires.IsZero() {
+			ttl = e.expires.Sub(now)
+		}
+		s.Entries = append(s.Entries, entrySnapshot{
+			Key:      e.key,
+			Val:      e.val,
+			Cost:     e.cost,
+			TTL:      ttl,
+			Freq:     e.freq,
+			Frequent: e.frequent,
+		})
+	}
+	if p, ok := c.policy.(*arcPolicy); ok {
+		s.P, s.B1, s.B2 = p.p, ghostKeys(p.b1), ghostKeys(p.b2)
+	}
+	return s
+}
+
+// restore rebuilds the cache from s. OnEvict is not encoded, the receiver
+// keeps its own, as well as its clock and statistics.
+func (c *Cache) restore(s snapshot) error {
+	if s.Policy > ARC {
+		return fmt.Errorf("%w: unknown cache policy %d", codec.ErrInvalidData, s.Policy)
+	}
+	if c.lk == nil {
+		c.lk = &sync.Mutex{}
+	}
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if c.now == nil {
+		c.now = time.Now
+	}
+	opts := Options{Policy: s.Policy, MaxSize: s.MaxSize, MaxCost: s.MaxCost, TTL: s.TTL, OnEvict: c.opts.OnEvict}
+	items := make(map[interface{}]*entry, len(s.Entries))
+	pol := newPolicy(opts.Policy, opts.MaxSize)
+	var cost uint64
+	now := c.now()
+	for _, es := range s.Entries {
+		if _, ok := items[es.Key]; ok {
+			return fmt.Errorf("%w: key %v appears twice", codec.ErrInvalidData, es.Key)
+		}
+		e := &entry{key: es.Key, val: es.Val, cost: es.Cost, freq: es.Freq, frequent: es.Frequent}
+		if es.TTL > 0 {
+			e.expires = now.Add(es.TTL)
+		}
+		items[e.key] = e
+		cost += e.cost
+		pol.restore(e)
+	}
+	if p, ok := pol.(*arcPolicy); ok {
+		p.p = s.P
+		for _, key := range s.B1 {
+			p.remember(key, false)
+		}
+		for _, key := range s.B2 {
+			p.remember(key, true)
+		}
+	}
+	c.opts, c.items, c.policy, c.cost = opts, items, pol, cost
+	return nil
+}
+
+// MarshalBinary gob-encodes the options and live entries, key and value
+// types must be registered with codec.Register.
+func (c *Cache) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary(c.snapshot())
+}
+
+func (c *Cache) UnmarshalBinary(data []byte) error {
+	var s snapshot
+	if err := codec.UnmarshalBinary(data, &s); err != nil {
+		return err
+	}
+	return c.restore(s)
+}
+
+func (c *Cache) MarshalJSON() ([]byte, error) {
+	s := c.snapshot()
+	js := jsonSnapshot{
+		Policy:  s.Policy,
+		MaxSize: s.MaxSize,
+		MaxCost: s.MaxCost,
+		TTL:     s.TTL,
+		Entries: make([]jsonEntrySnapshot, len(s.Entries)),
+		P:       s.P,
+	}
+	for i, e := range s.Entries {
+		key, err := codec.Wrap(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		val, err := codec.Wrap(e.Val)
+		if err != nil {
+			return nil, err
+		}
+		js.Entries[i] = jsonEntrySnapshot{Key: key, Val: val, Cost: e.Cost, TTL: e.TTL, Freq: e.Freq, Frequent: e.Frequent}
+	}
+	var err error
+	if js.B1, err = codec.WrapAll(s.B1); err != nil {
+		return nil, err
+	}
+	if js.B2, err = codec.WrapAll(s.B2); err != nil {
+		return nil, err
+	}
+	return json.Marshal(js)
+}
+
+func (c *Cache) UnmarshalJSON(data []byte) error {
+	var js jsonSnapshot
+	if err := json.Unmarshal(data, &js); err != nil {
+		return err
+	}
+	s := snapshot{
+		Policy:  js.Policy,
+		MaxSize: js.MaxSize,
+		MaxCost: js.MaxCost,
+		TTL:     js.TTL,
+		Entries: make([]entrySnapshot, len(js.Entries)),
+		P:       js.P,
+	}
+	for i, e := range js.Entries {
+		key, err := e.Key.Unwrap()
+		if err != nil {
+			return err
+		}
+		val, err := e.Val.Unwrap()
+		if err != nil {
+			return err
+		}
+		s.Entries[i] = entrySnapshot{Key: key, Val: val, Cost: e.Cost, TTL: e.TTL, Freq: e.Freq, Frequent: e.Frequent}
+	}
+	var err error
+	if s.B1, err = codec.UnwrapAll(js.B1); err != nil {
+		return err
+	}
+	if s.B2, err = codec.UnwrapAll(js.B2); err != nil {
+		return err
+	}
+	return c.restore(s)
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func TestCache_MarshalBinary(t *testing.T) {
+	should := fCache("MarshalBinary", t)
+	should("round trip through gob keeping LRU order", []bool{false, true, true, true}, func() interface{} {
+		c := fill(NewLRU(3), "a", "b", "c")
+		c.Get("a")
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(c); err != nil {
+			return err
+		}
+		var decoded Cache
+		if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+			return err
+		}
+		decoded.Set("d", "d")
+		return has(&decoded, "b", "a", "c", "d")
+	})
+	should("keep LFU counts", []bool{true, false, true}, func() interface{} {
+		c := fill(NewLFU(2), 1, 2)
+		c.Get(1)
+		c.Get(1)
+		data, err := c.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		decoded := NewLFU(0)
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			return err
+		}
+		decoded.Set(3, 3)
+		return has(decoded, 1, 2, 3)
+	})
+	should("refuse unknown policies", true, func() interface{} {
+		data, _ := codec.MarshalBinary(snapshot{Policy: ARC + 1})
+		return errors.Is(NewLRU(1).UnmarshalBinary(data), codec.ErrInvalidData)
+	})
+}
+
+func TestCache_MarshalJSON(t *testing.T) {
+	should := fCache("MarshalJSON", t)
+	should("keep remaining TTLs and drop expired entries", []interface{}{uint(1), true, false}, func() interface{} {
+		now := time.Unix(0, 0)
+		c := New(Options{Policy: LRU})
+		c.now = func() time.Time { return now }
+		c.SetWithTTL("short", 1, time.Second)
+		c.SetWithTTL("long", 2, time.Minute)
+		c.Set("gone", 3)
+		c.Remove("gone")
+		now = now.Add(2 * time.Second)
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		decoded := NewLRU(0)
+		decoded.now = func() time.Time { return now }
+		if err := json.Unmarshal(data, decoded); err != nil {
+			return err
+		}
+		size := decoded.Size()
+		now = now.Add(time.Minute - 3*time.Second)
+		alive := decoded.Contains("long")
+		now = now.Add(time.Second)
+		return []interface{}{size, alive, decoded.Contains("long")}
+	})
+	should("keep ARC frequent entries and ghosts", []bool{false, true, true, false}, func() interface{} {
+		c := fill(NewARC(2), "a", "b")
+		c.Get("a")
+		c.Set("c", "c")
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		decoded := NewARC(0)
+		if err := json.Unmarshal(data, decoded); err != nil {
+			return err
+		}
+		decoded.Set("b", "b")
+		return has(decoded, "a", "c", "b", "d")
+	})
+	should("round trip keeping key and value types", []interface{}{int64(7), true}, func() interface{} {
+		c := NewLRU(2)
+		c.Set(uint8(1), int64(7))
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		decoded := NewLRU(0)
+		if err := json.Unmarshal(data, decoded); err != nil {
+			return err
+		}
+		val, ok := decoded.Get(uint8(1))
+		return []interface{}{val, ok}
+	})
+}
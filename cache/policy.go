@@ -0,0 +1,328 @@
+package cache
+
+import (
+	"container/heap"
+	"container/list"
+	"sort"
+)
+
+type Policy uint8
+
+const (
+	LRU Policy = iota
+	LFU
+	ARC
+)
+
+func (p Policy) String() string {
+	switch p {
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	case ARC:
+		return "ARC"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// policy decides which entry goes next. admit is told about a new key
+// before any room is made for it, victim both picks and unlinks the entry,
+// remove unlinks an entry the cache dropped for some other reason. entries
+// lists live entries in an order that restore, called on each in turn,
+// rebuilds the same state from.
+type policy interface {
+	admit(key interface{})
+	insert(e *entry)
+	hit(e *entry)
+	remove(e *entry)
+	victim() *entry
+	clear()
+	entries() []*entry
+	restore(e *entry)
+}
+
+func newPolicy(p Policy, capacity uint) policy {
+	switch p {
+	case LRU:
+		return &lruPolicy{ll: list.New()}
+	case LFU:
+		return &lfuPolicy{}
+	case ARC:
+		return newARCPolicy(capacity)
+	default:
+		panic("[ERROR] unknown cache policy")
+	}
+}
+
+type lruPolicy struct {
+	ll *list.List
+}
+
+func (p *lruPolicy) admit(key interface{}) {}
+
+func (p *lruPolicy) insert(e *entry) { e.elem = p.ll.PushFront(e) }
+
+func (p *lruPolicy) hit(e *entry) { p.ll.MoveToFront(e.elem) }
+
+func (p *lruPolicy) remove(e *entry) { p.ll.Remove(e.elem) }
+
+func (p *lruPolicy) victim() *entry {
+	back := p.ll.Back()
+	if back == nil {
+		return nil
+	}
+	return p.ll.Remove(back).(*entry)
+}
+
+func (p *lruPolicy) clear() { p.ll.Init() }
+
+func (p *lruPolicy) entries() []*entry { return backToFront(p.ll) }
+
+func (p *lruPolicy) restore(e *entry) { p.insert(e) }
+
+type lfuPolicy struct {
+	h    lfuHeap
+	tick uint64
+}
+
+func (p *lfuPolicy) admit(key interface{}) {}
+
+func (p *lfuPolicy) insert(e *entry) {
+	p.tick++
+	e.freq = 1
+	e.tick = p.tick
+	heap.Push(&p.h, e)
+}
+
+func (p *lfuPolicy) hit(e *entry) {
+	p.tick++
+	e.freq++
+	e.tick = p.tick
+	heap.Fix(&p.h, e.idx)
+}
+
+func (p *lfuPolicy) remove(e *entry) { heap.Remove(&p.h, e.idx) }
+
+func (p *lfuPolicy) victim() *entry {
+	if len(p.h) == 0 {
+		return nil
+	}
+	return heap.Pop(&p.h).(*entry)
+}
+
+func (p *lfuPolicy) clear() { p.h = nil }
+
+func (p *lfuPolicy) entries() []*entry {
+	es := append([]*entry(nil), p.h...)
+	sort.Slice(es, func(i, j int) bool { return es[i].tick < es[j].tick })
+	return es
+}
+
+func (p *lfuPolicy) restore(e *entry) {
+	p.tick++
+	e.tick = p.tick
+	heap.Push(&p.h, e)
+}
+
+// lfuHeap orders by frequency, falling back to recency for equal counts.
+type lfuHeap []*entry
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].freq == h[j].freq {
+		return h[i].tick < h[j].tick
+	}
+	return h[i].freq < h[j].freq
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].idx = i
+	h[j].idx = j
+}
+
+func (h *lfuHeap) Push(x interface{}) {
+	e := x.(*entry)
+	e.idx = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *lfuHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
+
+// arcPolicy is the Adaptive Replacement Cache of Megiddo & Modha. t1 and t2
+// hold live entries seen once and more than once, b1 and b2 remember the keys
+// recently evicted from each and steer the target size p of t1. A ghost hit
+// adapts p in admit, so that the eviction making room for the key already
+// uses the new target.
+type arcPolicy struct {
+	c      int
+	p      int
+	t1     *list.List
+	t2     *list.List
+	b1     *list.List
+	b2     *list.List
+	ghosts map[interface{}]*list.Element
+	// lastB2 and ghostHit describe the key last passed to admit.
+	lastB2   bool
+	ghostHit bool
+}
+
+func newARCPolicy(capacity uint) *arcPolicy {
+	return &arcPolicy{
+		c:      int(capacity),
+		t1:     list.New(),
+		t2:     list.New(),
+		b1:     list.New(),
+		b2:     list.New(),
+		ghosts: make(map[interface{}]*list.Element),
+	}
+}
+
+func (p *arcPolicy) capacity() int {
+	if p.c > 0 {
+		return p.c
+	}
+	if n := p.t1.Len() + p.t2.Len(); n > 0 {
+		return n
+	}
+	return 1
+}
+
+func (p *arcPolicy) admit(key interface{}) {
+	p.lastB2, p.ghostHit = false, false
+	ghost, ok := p.ghosts[key]
+	if !ok {
+		return
+	}
+	delete(p.ghosts, key)
+	p.ghostHit = true
+	if ghost.Value.(*arcGhost).inB2 {
+		p.p -= maxInt(p.b1.Len()/p.b2.Len(), 1)
+		if p.p < 0 {
+			p.p = 0
+		}
+		p.b2.Remove(ghost)
+		p.lastB2 = true
+	} else {
+		p.p += maxInt(p.b2.Len()/p.b1.Len(), 1)
+		if c := p.capacity(); p.p > c {
+			p.p = c
+		}
+		p.b1.Remove(ghost)
+	}
+}
+
+func (p *arcPolicy) insert(e *entry) {
+	if p.ghostHit {
+		p.ghostHit = false
+		e.elem = p.t2.PushFront(e)
+		e.frequent = true
+		return
+	}
+	e.elem = p.t1.PushFront(e)
+	e.frequent = false
+}
+
+func (p *arcPolicy) hit(e *entry) {
+	p.unlink(e)
+	e.elem = p.t2.PushFront(e)
+	e.frequent = true
+}
+
+func (p *arcPolicy) remove(e *entry) { p.unlink(e) }
+
+func (p *arcPolicy) victim() *entry {
+	var from *list.List
+	t1 := p.t1.Len()
+	switch {
+	case t1 > 0 && (t1 > p.p || (p.lastB2 && t1 == p.p) || p.t2.Len() == 0):
+		from = p.t1
+	case p.t2.Len() > 0:
+		from = p.t2
+	default:
+		return nil
+	}
+	e := from.Remove(from.Back()).(*entry)
+	p.remember(e.key, from == p.t2)
+	return e
+}
+
+func (p *arcPolicy) clear() {
+	p.p = 0
+	p.lastB2, p.ghostHit = false, false
+	p.t1.Init()
+	p.t2.Init()
+	p.b1.Init()
+	p.b2.Init()
+	p.ghosts = make(map[interface{}]*list.Element)
+}
+
+func (p *arcPolicy) entries() []*entry {
+	return append(backToFront(p.t1), backToFront(p.t2)...)
+}
+
+func (p *arcPolicy) restore(e *entry) {
+	if e.frequent {
+		e.elem = p.t2.PushFront(e)
+	} else {
+		e.elem = p.t1.PushFront(e)
+	}
+}
+
+// ghostKeys lists the keys remembered in b, oldest first.
+func ghostKeys(b *list.List) []interface{} {
+	keys := make([]interface{}, 0, b.Len())
+	for el := b.Back(); el != nil; el = el.Prev() {
+		keys = append(keys, el.Value.(*arcGhost).key)
+	}
+	return keys
+}
+
+func (p *arcPolicy) unlink(e *entry) {
+	if e.frequent {
+		p.t2.Remove(e.elem)
+	} else {
+		p.t1.Remove(e.elem)
+	}
+}
+
+func (p *arcPolicy) remember(key interface{}, inB2 bool) {
+	ghosts := p.b1
+	if inB2 {
+		ghosts = p.b2
+	}
+	p.ghosts[key] = ghosts.PushFront(&arcGhost{key: key, inB2: inB2})
+	for c := p.capacity(); ghosts.Len() > c; {
+		delete(p.ghosts, ghosts.Remove(ghosts.Back()).(*arcGhost).key)
+	}
+}
+
+type arcGhost struct {
+	key  interface{}
+	inB2 bool
+}
+
+func backToFront(ll *list.List) []*entry {
+	es := make([]*entry, 0, ll.Len())
+	for el := ll.Back(); el != nil; el = el.Prev() {
+		es = append(es, el.Value.(*entry))
+	}
+	return es
+}
+
+func maxInt(x int, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
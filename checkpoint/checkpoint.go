@@ -0,0 +1,130 @@
+package checkpoint
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Record is an element of a resumable source. Pos is where reading resumes
+// once this element and every one before it has been acknowledged.
+type Record struct {
+	Seq uint64
+	Pos int64
+	Val interface{}
+}
+
+func (r *Record) String() string {
+	return fmt.Sprintf("%v@%d", r.Val, r.Pos)
+}
+
+// Checkpointer tracks which records have been acknowledged and saves the
+// position after the longest acknowledged prefix. Records after that may be
+// emitted again after a restart, which gives at-least-once delivery as long
+// as records are only acknowledged once a sink is done with them.
+type Checkpointer struct {
+	store   Store
+	key     string
+	every   uint
+	lk      *sync.Mutex
+	issued  uint64
+	next    uint64
+	acked   map[uint64]int64
+	pos     int64
+	unsaved uint
+	err     error
+}
+
+// New saves to store under key after every nth position advance, 0 saves
+// only on Flush.
+func New(store Store, key string, every uint) *Checkpointer {
+	return &Checkpointer{
+		store: store,
+		key:   key,
+		every: every,
+		lk:    &sync.Mutex{},
+		acked: make(map[uint64]int64),
+	}
+}
+
+// Start loads the saved position, 0 when there is none, and forgets records
+// tracked so far. Sources call it before reading.
+func (cp *Checkpointer) Start() (int64, error) {
+	pos, _, err := cp.store.Load(cp.key)
+	if err != nil {
+		return 0, err
+	}
+	cp.lk.Lock()
+	defer cp.lk.Unlock()
+	cp.issued, cp.next, cp.pos, cp.unsaved = 0, 0, pos, 0
+	cp.acked = make(map[uint64]int64)
+	return pos, nil
+}
+
+// Track wraps val, read up to pos, in the next Record.
+func (cp *Checkpointer) Track(pos int64, val interface{}) *Record {
+	cp.lk.Lock()
+	defer cp.lk.Unlock()
+	r := &Record{Seq: cp.issued, Pos: pos, Val: val}
+	cp.issued++
+	return r
+}
+
+// Ack marks a *Record as done, records can be acknowledged in any order.
+// Anything else is ignored so Ack can be passed straight to Stream.ForEach.
+// Save failures are reported by Err.
+func (cp *Checkpointer) Ack(x interface{}) {
+	r, ok := x.(*Record)
+	if !ok {
+		return
+	}
+	cp.lk.Lock()
+	defer cp.lk.Unlock()
+	if r.Seq < cp.next {
+		return
+	}
+	cp.acked[r.Seq] = r.Pos
+	for pos, ok := cp.acked[cp.next]; ok; pos, ok = cp.acked[cp.next] {
+		delete(cp.acked, cp.next)
+		cp.next++
+		cp.pos = pos
+		cp.unsaved++
+	}
+	if cp.every > 0 && cp.unsaved >= cp.every {
+		if err := cp.save(); err != nil {
+			cp.err = err
+		}
+	}
+}
+
+// Position is where a restart would resume from.
+func (cp *Checkpointer) Position() int64 {
+	cp.lk.Lock()
+	defer cp.lk.Unlock()
+	return cp.pos
+}
+
+// Flush saves the current position, call it once a job is done.
+func (cp *Checkpointer) Flush() error {
+	cp.lk.Lock()
+	defer cp.lk.Unlock()
+	return cp.save()
+}
+
+// Err is the last error from a save triggered by Ack.
+func (cp *Checkpointer) Err() error {
+	cp.lk.Lock()
+	defer cp.lk.Unlock()
+	return cp.err
+}
+
+func (cp *Checkpointer) save() error {
+	if err := cp.store.Save(cp.key, cp.pos); err != nil {
+		return err
+	}
+	cp.unsaved = 0
+	return nil
+}
+
+func (cp *Checkpointer) String() string {
+	return fmt.Sprintf("Checkpointer(%s@%d)", cp.key, cp.Position())
+}
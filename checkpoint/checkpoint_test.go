@@ -0,0 +1,67 @@
+package checkpoint
+
+import (
+	"errors"
+	"testing"
+
+	ut "github.com/nl253/Testing"
+)
+
+var fCheckpointer = ut.Test("Checkpointer")
+
+type failingStore struct{ *MemStore }
+
+var errDisk = errors.New("disk")
+
+func (failingStore) Save(string, int64) error { return errDisk }
+
+func TestCheckpointer_Ack(t *testing.T) {
+	should := fCheckpointer("Ack", t)
+	should("only advance over acknowledged prefixes", []int64{0, 0, 30, 40}, func() interface{} {
+		cp := New(NewMemStore(), "job", 0)
+		_, _ = cp.Start()
+		rs := []*Record{cp.Track(10, "a"), cp.Track(20, "b"), cp.Track(30, "c"), cp.Track(40, "d")}
+		got := make([]int64, 0)
+		cp.Ack(rs[1])
+		got = append(got, cp.Position())
+		cp.Ack(rs[2])
+		got = append(got, cp.Position())
+		cp.Ack(rs[0])
+		got = append(got, cp.Position())
+		cp.Ack(rs[3])
+		cp.Ack(rs[3])
+		return append(got, cp.Position())
+	})
+	should("save every n advances", []int64{2, 4}, func() interface{} {
+		store := NewMemStore()
+		cp := New(store, "job", 2)
+		_, _ = cp.Start()
+		got := make([]int64, 0)
+		for i := int64(1); i <= 5; i++ {
+			cp.Ack(cp.Track(i, i))
+			if pos, ok, _ := store.Load("job"); ok && (len(got) == 0 || got[len(got)-1] != pos) {
+				got = append(got, pos)
+			}
+		}
+		return got
+	})
+	should("report failed saves", errDisk, func() interface{} {
+		cp := New(failingStore{NewMemStore()}, "job", 1)
+		_, _ = cp.Start()
+		cp.Ack(cp.Track(1, 1))
+		return cp.Err()
+	})
+}
+
+func TestCheckpointer_Start(t *testing.T) {
+	should := fCheckpointer("Start", t)
+	should("resume from the saved position", int64(5), func() interface{} {
+		store := NewMemStore()
+		cp := New(store, "job", 0)
+		_, _ = cp.Start()
+		cp.Ack(cp.Track(5, "x"))
+		_ = cp.Flush()
+		pos, _ := New(store, "job", 0).Start()
+		return pos
+	})
+}
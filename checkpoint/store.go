@@ -0,0 +1,111 @@
+package checkpoint
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrCorrupt    = errors.New("checkpoint: corrupt checkpoint")
+	ErrInvalidKey = errors.New("checkpoint: key is not a plain file name")
+)
+
+// Store persists one position per key.
+type Store interface {
+	// Load reports false when nothing was saved under key yet.
+	Load(key string) (int64, bool, error)
+	Save(key string, pos int64) error
+}
+
+// FileStore keeps every key in its own file in a directory. Saves write a
+// temporary file and rename it so a crash leaves either the old or the new
+// position behind. Keys that are empty, . or .. or contain a path separator
+// are refused with ErrInvalidKey.
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (fs *FileStore) path(key string) string {
+	return filepath.Join(fs.dir, key+".checkpoint")
+}
+
+func validKey(key string) bool {
+	return key != "" && key != "." && key != ".." && !strings.ContainsAny(key, `/\`)
+}
+
+func (fs *FileStore) Load(key string) (int64, bool, error) {
+	if !validKey(key) {
+		return 0, false, ErrInvalidKey
+	}
+	data, err := ioutil.ReadFile(fs.path(key))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	pos, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || pos < 0 {
+		return 0, false, ErrCorrupt
+	}
+	return pos, true, nil
+}
+
+func (fs *FileStore) Save(key string, pos int64) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	tmp, err := ioutil.TempFile(fs.dir, key+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strconv.FormatInt(pos, 10) + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fs.path(key))
+}
+
+// MemStore keeps positions in memory, it is mostly useful in tests.
+type MemStore struct {
+	lk  *sync.Mutex
+	pos map[string]int64
+}
+
+func NewMemStore() *MemStore {
+	return &MemStore{lk: &sync.Mutex{}, pos: make(map[string]int64)}
+}
+
+func (ms *MemStore) Load(key string) (int64, bool, error) {
+	ms.lk.Lock()
+	defer ms.lk.Unlock()
+	pos, ok := ms.pos[key]
+	return pos, ok, nil
+}
+
+func (ms *MemStore) Save(key string, pos int64) error {
+	ms.lk.Lock()
+	ms.pos[key] = pos
+	ms.lk.Unlock()
+	return nil
+}
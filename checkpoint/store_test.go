@@ -0,0 +1,61 @@
+package checkpoint
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	ut "github.com/nl253/Testing"
+)
+
+var fStore = ut.Test("Store")
+
+func TestFileStore_Save(t *testing.T) {
+	should := fStore("FileStore", t)
+	should("load what was saved", []interface{}{int64(42), true, nil}, func() interface{} {
+		fs, _ := NewFileStore(t.TempDir())
+		_ = fs.Save("job", 7)
+		_ = fs.Save("job", 42)
+		pos, ok, err := fs.Load("job")
+		return []interface{}{pos, ok, err}
+	})
+	should("report missing keys", []interface{}{int64(0), false, nil}, func() interface{} {
+		fs, _ := NewFileStore(filepath.Join(t.TempDir(), "nested"))
+		pos, ok, err := fs.Load("job")
+		return []interface{}{pos, ok, err}
+	})
+	should("leave no temporary files behind", 1, func() interface{} {
+		dir := t.TempDir()
+		fs, _ := NewFileStore(dir)
+		_ = fs.Save("job", 1)
+		_ = fs.Save("job", 2)
+		entries, _ := ioutil.ReadDir(dir)
+		return len(entries)
+	})
+	should("refuse corrupt checkpoints", ErrCorrupt, func() interface{} {
+		dir := t.TempDir()
+		fs, _ := NewFileStore(dir)
+		_ = ioutil.WriteFile(filepath.Join(dir, "job.checkpoint"), []byte("x"), 0644)
+		_, _, err := fs.Load("job")
+		return err
+	})
+	should("refuse keys that are not plain file names", []interface{}{ErrInvalidKey, ErrInvalidKey, ErrInvalidKey, ErrInvalidKey, 0}, func() interface{} {
+		root := t.TempDir()
+		dir := filepath.Join(root, "store")
+		fs, _ := NewFileStore(dir)
+		errs := []interface{}{fs.Save("../x", 1), fs.Save("a/b", 1), fs.Save("..", 1)}
+		_, _, loadErr := fs.Load("../x")
+		entries, _ := ioutil.ReadDir(root)
+		return append(errs, loadErr, len(entries)-1)
+	})
+}
+
+func TestMemStore_Save(t *testing.T) {
+	should := fStore("MemStore", t)
+	should("load what was saved", []interface{}{int64(3), true}, func() interface{} {
+		ms := NewMemStore()
+		_ = ms.Save("job", 3)
+		pos, ok, _ := ms.Load("job")
+		return []interface{}{pos, ok}
+	})
+}
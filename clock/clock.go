@@ -0,0 +1,142 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for time-aware operators. Real is backed by
+// package time, Virtual only moves when told to.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+type Timer interface {
+	Chan() <-chan time.Time
+	Stop() bool
+}
+
+type realClock struct{}
+
+type realTimer struct{ t *time.Timer }
+
+func Real() Clock { return realClock{} }
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) NewTimer(d time.Duration) Timer { return &realTimer{time.NewTimer(d)} }
+
+func (t *realTimer) Chan() <-chan time.Time { return t.t.C }
+
+func (t *realTimer) Stop() bool { return t.t.Stop() }
+
+// Virtual is a manually driven Clock for tests. Timers fire, in deadline
+// order, only when Advance moves the clock past them.
+type Virtual struct {
+	lk      *sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	timers  []*virtualTimer
+	created uint64
+}
+
+type virtualTimer struct {
+	v        *Virtual
+	deadline time.Time
+	c        chan time.Time
+}
+
+func NewVirtual(start time.Time) *Virtual {
+	lk := &sync.Mutex{}
+	return &Virtual{lk: lk, cond: sync.NewCond(lk), now: start}
+}
+
+func (v *Virtual) Now() time.Time {
+	v.lk.Lock()
+	defer v.lk.Unlock()
+	return v.now
+}
+
+func (v *Virtual) Sleep(d time.Duration) { <-v.After(d) }
+
+func (v *Virtual) After(d time.Duration) <-chan time.Time { return v.NewTimer(d).Chan() }
+
+func (v *Virtual) NewTimer(d time.Duration) Timer {
+	v.lk.Lock()
+	defer v.lk.Unlock()
+	t := &virtualTimer{v: v, deadline: v.now.Add(d), c: make(chan time.Time, 1)}
+	v.created++
+	if d <= 0 {
+		t.c <- v.now
+	} else {
+		v.timers = append(v.timers, t)
+		sort.SliceStable(v.timers, func(i, j int) bool { return v.timers[i].deadline.Before(v.timers[j].deadline) })
+	}
+	v.cond.Broadcast()
+	return t
+}
+
+// Advance moves the clock forward by d, firing every timer that falls due.
+func (v *Virtual) Advance(d time.Duration) {
+	v.lk.Lock()
+	defer v.lk.Unlock()
+	target := v.now.Add(d)
+	for len(v.timers) > 0 && !v.timers[0].deadline.After(target) {
+		t := v.timers[0]
+		v.timers = v.timers[1:]
+		v.now = t.deadline
+		t.c <- v.now
+	}
+	v.now = target
+	v.cond.Broadcast()
+}
+
+// Pending counts timers (and sleepers) that have not fired yet.
+func (v *Virtual) Pending() int {
+	v.lk.Lock()
+	defer v.lk.Unlock()
+	return len(v.timers)
+}
+
+// BlockUntil waits until at least n timers are pending, so a test knows the
+// code under test has started waiting before it calls Advance.
+func (v *Virtual) BlockUntil(n int) {
+	v.lk.Lock()
+	defer v.lk.Unlock()
+	for len(v.timers) < n {
+		v.cond.Wait()
+	}
+}
+
+// BlockUntilCreated waits until n timers have been created in total, which
+// also covers timers that were stopped or fired immediately.
+func (v *Virtual) BlockUntilCreated(n uint64) {
+	v.lk.Lock()
+	defer v.lk.Unlock()
+	for v.created < n {
+		v.cond.Wait()
+	}
+}
+
+func (t *virtualTimer) Chan() <-chan time.Time { return t.c }
+
+func (t *virtualTimer) Stop() bool {
+	t.v.lk.Lock()
+	defer t.v.lk.Unlock()
+	for i, other := range t.v.timers {
+		if other == t {
+			t.v.timers = append(t.v.timers[:i], t.v.timers[i+1:]...)
+			t.v.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
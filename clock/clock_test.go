@@ -0,0 +1,72 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	ut "github.com/nl253/Testing"
+)
+
+var fVirtual = ut.Test("Virtual")
+
+var epoch = time.Unix(0, 0)
+
+func TestVirtual_Advance(t *testing.T) {
+	should := fVirtual("Advance", t)
+	should("move Now forward", epoch.Add(time.Hour), func() interface{} {
+		v := NewVirtual(epoch)
+		v.Advance(time.Hour)
+		return v.Now()
+	})
+	should("fire due timers with their deadline", epoch.Add(time.Second), func() interface{} {
+		v := NewVirtual(epoch)
+		c := v.After(time.Second)
+		v.Advance(time.Minute)
+		return <-c
+	})
+	should("not fire timers that are not due", 1, func() interface{} {
+		v := NewVirtual(epoch)
+		v.After(time.Second)
+		v.Advance(time.Second - 1)
+		return v.Pending()
+	})
+	should("fire non-positive durations immediately", epoch, func() interface{} {
+		return <-NewVirtual(epoch).After(0)
+	})
+}
+
+func TestVirtual_Sleep(t *testing.T) {
+	should := fVirtual("Sleep", t)
+	should("wake sleepers without waiting for real time", true, func() interface{} {
+		v := NewVirtual(epoch)
+		done := make(chan bool)
+		go func() {
+			v.Sleep(10 * time.Hour)
+			done <- true
+		}()
+		v.BlockUntil(1)
+		v.Advance(10 * time.Hour)
+		return <-done
+	})
+}
+
+func TestVirtual_NewTimer(t *testing.T) {
+	should := fVirtual("NewTimer", t)
+	should("not fire stopped timers", []interface{}{true, 0, uint64(1)}, func() interface{} {
+		v := NewVirtual(epoch)
+		stopped := v.NewTimer(time.Second).Stop()
+		v.Advance(time.Minute)
+		v.BlockUntilCreated(1)
+		return []interface{}{stopped, v.Pending(), v.created}
+	})
+}
+
+func TestReal(t *testing.T) {
+	should := fVirtual("Real", t)
+	should("sleep for real", true, func() interface{} {
+		c := Real()
+		start := c.Now()
+		c.Sleep(10 * time.Millisecond)
+		return c.Now().Sub(start) >= 10*time.Millisecond
+	})
+}
@@ -0,0 +1,158 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	ErrUnregistered = errors.New("codec: type is not registered")
+	ErrUnknownName  = errors.New("codec: unknown type name")
+	ErrInvalidData  = errors.New("codec: invalid serialized data")
+)
+
+// Nil is the name written for nil elements.
+const Nil = "nil"
+
+// registry maps names to types so interface{} elements decode to the type
+// they were encoded from. Names are shared with encoding/gob.
+var registry = struct {
+	lk     *sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	lk:     &sync.RWMutex{},
+	byName: make(map[string]reflect.Type),
+	byType: make(map[reflect.Type]string),
+}
+
+func init() {
+	for _, x := range []interface{}{
+		false, "",
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0),
+		[]byte{}, []interface{}{}, map[string]interface{}{},
+	} {
+		Register(reflect.TypeOf(x).String(), x)
+	}
+}
+
+// Register makes the type of x available under name. Registering the same
+// pair again is harmless, reusing a name or type for something else panics.
+func Register(name string, x interface{}) {
+	typ := reflect.TypeOf(x)
+	if name == Nil || typ == nil {
+		panic(fmt.Sprintf("[ERROR] cannot register %v as %q", x, name))
+	}
+	registry.lk.Lock()
+	defer registry.lk.Unlock()
+	if known, ok := registry.byName[name]; ok && known != typ {
+		panic(fmt.Sprintf("[ERROR] name %q is already registered for %v", name, known))
+	}
+	if known, ok := registry.byType[typ]; ok && known != name {
+		panic(fmt.Sprintf("[ERROR] type %v is already registered as %q", typ, known))
+	}
+	registry.byName[name] = typ
+	registry.byType[typ] = name
+	gob.RegisterName(name, x)
+}
+
+// Name reports what the type of x is registered as.
+func Name(x interface{}) (string, bool) {
+	if x == nil {
+		return Nil, true
+	}
+	registry.lk.RLock()
+	defer registry.lk.RUnlock()
+	name, ok := registry.byType[reflect.TypeOf(x)]
+	return name, ok
+}
+
+func lookup(name string) (reflect.Type, bool) {
+	registry.lk.RLock()
+	defer registry.lk.RUnlock()
+	typ, ok := registry.byName[name]
+	return typ, ok
+}
+
+// Value is the JSON form of an element, its registered type name next to
+// its own JSON encoding.
+type Value struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func Wrap(x interface{}) (Value, error) {
+	name, ok := Name(x)
+	if !ok {
+		return Value{}, fmt.Errorf("%w: %T", ErrUnregistered, x)
+	}
+	if x == nil {
+		return Value{Type: Nil}, nil
+	}
+	raw, err := json.Marshal(x)
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{Type: name, Value: raw}, nil
+}
+
+func (v Value) Unwrap() (interface{}, error) {
+	if v.Type == Nil {
+		return nil, nil
+	}
+	typ, ok := lookup(v.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownName, v.Type)
+	}
+	ptr := reflect.New(typ)
+	if err := json.Unmarshal(v.Value, ptr.Interface()); err != nil {
+		return nil, err
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+func WrapAll(xs []interface{}) ([]Value, error) {
+	vs := make([]Value, len(xs))
+	for i, x := range xs {
+		v, err := Wrap(x)
+		if err != nil {
+			return nil, err
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+func UnwrapAll(vs []Value) ([]interface{}, error) {
+	xs := make([]interface{}, len(vs))
+	for i, v := range vs {
+		x, err := v.Unwrap()
+		if err != nil {
+			return nil, err
+		}
+		xs[i] = x
+	}
+	return xs, nil
+}
+
+// MarshalBinary gob-encodes x, which is usually a snapshot struct holding
+// interface{} elements.
+func MarshalBinary(x interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(x); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes data produced by MarshalBinary into the pointer x.
+func UnmarshalBinary(data []byte, x interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(x)
+}
@@ -0,0 +1,81 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	ut "github.com/nl253/Testing"
+)
+
+var fCodec = ut.Test("Codec")
+
+type point struct {
+	X int
+	Y int
+}
+
+func init() {
+	Register("codec.point", point{})
+}
+
+func TestCodec_Register(t *testing.T) {
+	should := fCodec("Register", t)
+	should("accept the same pair twice", "codec.point", func() interface{} {
+		Register("codec.point", point{})
+		name, _ := Name(point{})
+		return name
+	})
+	should("refuse to reuse a name", true, func() (panicked interface{}) {
+		defer func() { panicked = recover() != nil }()
+		Register("codec.point", &point{})
+		return false
+	})
+}
+
+func TestCodec_Wrap(t *testing.T) {
+	should := fCodec("Wrap", t)
+	xs := []interface{}{1, uint8(2), 3.5, "s", nil, true, point{1, 2}, []byte("b")}
+	should("round trip through JSON keeping types", xs, func() interface{} {
+		vs, err := WrapAll(xs)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(vs)
+		if err != nil {
+			return err
+		}
+		var decoded []Value
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+		ys, err := UnwrapAll(decoded)
+		if err != nil {
+			return err
+		}
+		return ys
+	})
+	should("refuse unregistered types", true, func() interface{} {
+		_, err := Wrap(struct{}{})
+		return errors.Is(err, ErrUnregistered)
+	})
+	should("refuse unknown names", true, func() interface{} {
+		_, err := Value{Type: "nope"}.Unwrap()
+		return errors.Is(err, ErrUnknownName)
+	})
+}
+
+func TestCodec_MarshalBinary(t *testing.T) {
+	should := fCodec("MarshalBinary", t)
+	should("round trip interface{} elements", []interface{}{1, "s", point{3, 4}}, func() interface{} {
+		data, err := MarshalBinary([]interface{}{1, "s", point{3, 4}})
+		if err != nil {
+			return err
+		}
+		var xs []interface{}
+		if err := UnmarshalBinary(data, &xs); err != nil {
+			return err
+		}
+		return xs
+	})
+}
@@ -9,7 +9,7 @@ type Cloneable interface {
 }
 
 type Equatable interface {
-	Eq() bool
+	Eq(x interface{}) bool
 }
 
 type IObject interface {
@@ -18,3 +18,11 @@ type IObject interface {
 	Equatable
 	New() *IObject
 }
+
+// Eq compares x and y with x.Eq when x is Equatable and with == otherwise.
+func Eq(x interface{}, y interface{}) bool {
+	if e, ok := x.(Equatable); ok {
+		return e.Eq(y)
+	}
+	return x == y
+}
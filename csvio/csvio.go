@@ -0,0 +1,141 @@
+package csvio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrUnknownRecord = errors.New("csvio: element is not a record")
+
+// Options mirror the settings of encoding/csv. The zero value reads comma
+// separated records without a header.
+type Options struct {
+	Comma            rune
+	Comment          rune
+	LazyQuotes       bool
+	TrimLeadingSpace bool
+	// FieldsPerRecord is as in csv.Reader, 0 means every record must have
+	// as many fields as the first one and -1 allows any number.
+	FieldsPerRecord int
+	// Header turns the first record into keys and every later record into
+	// a map[string]string.
+	Header bool
+}
+
+// Decoder reads records lazily, each one is a fresh []string or, with a
+// header, a map[string]string.
+type Decoder struct {
+	reader *csv.Reader
+	opts   Options
+	header []string
+	err    error
+}
+
+func NewDecoder(r io.Reader, opts Options) *Decoder {
+	reader := csv.NewReader(r)
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	reader.Comment = opts.Comment
+	reader.LazyQuotes = opts.LazyQuotes
+	reader.TrimLeadingSpace = opts.TrimLeadingSpace
+	reader.FieldsPerRecord = opts.FieldsPerRecord
+	return &Decoder{reader: reader, opts: opts}
+}
+
+// Next returns the next record, or false once the input is exhausted or
+// malformed, in which case Err says why.
+func (d *Decoder) Next() (interface{}, bool) {
+	if d.err != nil {
+		return nil, false
+	}
+	record, err := d.reader.Read()
+	if err == nil && d.opts.Header && d.header == nil {
+		d.header = record
+		record, err = d.reader.Read()
+	}
+	if err != nil {
+		if err != io.EOF {
+			d.err = err
+		}
+		return nil, false
+	}
+	if !d.opts.Header {
+		return record, true
+	}
+	m := make(map[string]string, len(d.header))
+	for i, key := range d.header {
+		if i < len(record) {
+			m[key] = record[i]
+		}
+	}
+	return m, true
+}
+
+// Header is nil until the first record has been read.
+func (d *Decoder) Header() []string { return d.header }
+
+func (d *Decoder) Err() error { return d.err }
+
+// Record turns an element into fields. Maps are read in the order of
+// columns, slices are taken as they are.
+func Record(x interface{}, columns []string) ([]string, error) {
+	switch x.(type) {
+	case []string:
+		return x.([]string), nil
+	case []interface{}:
+		xs := x.([]interface{})
+		record := make([]string, len(xs))
+		for i, field := range xs {
+			record[i] = fmt.Sprint(field)
+		}
+		return record, nil
+	case map[string]string:
+		m := x.(map[string]string)
+		record := make([]string, len(columns))
+		for i, key := range columns {
+			record[i] = m[key]
+		}
+		return record, nil
+	case map[string]interface{}:
+		m := x.(map[string]interface{})
+		record := make([]string, len(columns))
+		for i, key := range columns {
+			if field, ok := m[key]; ok && field != nil {
+				record[i] = fmt.Sprint(field)
+			}
+		}
+		return record, nil
+	default:
+		return nil, ErrUnknownRecord
+	}
+}
+
+// WriteRecord writes fields as one line of CSV, quoting where needed.
+func WriteRecord(w io.Writer, fields []string) (int, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(fields); err != nil {
+		return 0, err
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, err
+	}
+	return w.Write(buf.Bytes())
+}
+
+// Encoder writes elements with WriteRecord after converting them with
+// Record, its signature matches stream.Encoder.
+func Encoder(columns []string) func(w io.Writer, x interface{}) (int, error) {
+	return func(w io.Writer, x interface{}) (int, error) {
+		record, err := Record(x, columns)
+		if err != nil {
+			return 0, err
+		}
+		return WriteRecord(w, record)
+	}
+}
@@ -0,0 +1,63 @@
+package csvio
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+
+	ut "github.com/nl253/Testing"
+)
+
+var fDecoder = ut.Test("Decoder")
+
+const input = "name,note\nann,\"likes, commas\"\nbob,\"says \"\"hi\"\"\"\n"
+
+func decodeAll(d *Decoder) []interface{} {
+	xs := make([]interface{}, 0)
+	for x, ok := d.Next(); ok; x, ok = d.Next() {
+		xs = append(xs, x)
+	}
+	return xs
+}
+
+func TestDecoder_Next(t *testing.T) {
+	should := fDecoder("Next", t)
+	should("read quoted records", []interface{}{
+		[]string{"name", "note"},
+		[]string{"ann", "likes, commas"},
+		[]string{"bob", "says \"hi\""},
+	}, func() interface{} {
+		return decodeAll(NewDecoder(strings.NewReader(input), Options{}))
+	})
+	should("key records by header", []interface{}{
+		map[string]string{"name": "ann", "note": "likes, commas"},
+		map[string]string{"name": "bob", "note": "says \"hi\""},
+	}, func() interface{} {
+		return decodeAll(NewDecoder(strings.NewReader(input), Options{Header: true}))
+	})
+	should("use a custom delimiter and comments", []interface{}{[]string{"a", "b,c"}}, func() interface{} {
+		return decodeAll(NewDecoder(strings.NewReader("# skipped\na;b,c\n"), Options{Comma: ';', Comment: '#'}))
+	})
+	should("report malformed records", true, func() interface{} {
+		d := NewDecoder(strings.NewReader("a,b\nc\n"), Options{})
+		decodeAll(d)
+		return errors.Is(d.Err(), csv.ErrFieldCount)
+	})
+}
+
+func TestEncoder(t *testing.T) {
+	should := ut.Test("Encoder")("Encoder", t)
+	should("write maps in column order and quote fields", "b,\"x, y\"\n,1\n", func() interface{} {
+		var buf bytes.Buffer
+		enc := Encoder([]string{"k", "v"})
+		_, _ = enc(&buf, map[string]string{"v": "x, y", "k": "b"})
+		_, _ = enc(&buf, map[string]interface{}{"v": 1})
+		return buf.String()
+	})
+	should("refuse elements that are not records", ErrUnknownRecord, func() interface{} {
+		_, err := Encoder(nil)(&bytes.Buffer{}, 42)
+		return err
+	})
+}
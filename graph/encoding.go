@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func init() {
+	codec.Register("graph.Graph", &Graph{})
+}
+
+// edgeRef refers to vertices by their position so each vertex is only
+// encoded once.
+type edgeRef struct {
+	From   int
+	To     int
+	Weight float64
+}
+
+type snapshot struct {
+	Directed bool
+	Vertices []interface{}
+	Edges    []edgeRef
+}
+
+type jsonSnapshot struct {
+	Directed bool          `json:"directed"`
+	Vertices []codec.Value `json:"vertices"`
+	Edges    []edgeRef     `json:"edges"`
+}
+
+// snapshot fails with codec.ErrInvalidData when an edge ends at a vertex
+// that cannot be looked up, such as a NaN.
+func (g *Graph) snapshot() (snapshot, error) {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	vertices := append([]interface{}(nil), g.vertices...)
+	idx := make(map[interface{}]int, len(vertices))
+	for i, v := range vertices {
+		idx[v] = i
+	}
+	edges := make([]edgeRef, 0)
+	for _, x := range g.edges() {
+		e := x.(*Edge)
+		from, okFrom := idx[e.From]
+		to, okTo := idx[e.To]
+		if !okFrom || !okTo {
+			return snapshot{}, fmt.Errorf("%w: edge %v has an endpoint that is not a vertex", codec.ErrInvalidData, e)
+		}
+		edges = append(edges, edgeRef{From: from, To: to, Weight: e.Weight})
+	}
+	return snapshot{Directed: g.directed, Vertices: vertices, Edges: edges}, nil
+}
+
+// restore rebuilds g from s, g may be the zero value.
+func (g *Graph) restore(s snapshot) error {
+	newG := New(s.Directed)
+	for _, v := range s.Vertices {
+		newG.addVertex(v)
+	}
+	for _, e := range s.Edges {
+		if e.From < 0 || e.From >= len(s.Vertices) || e.To < 0 || e.To >= len(s.Vertices) {
+			return codec.ErrInvalidData
+		}
+		newG.AddEdge(s.Vertices[e.From], s.Vertices[e.To], e.Weight)
+	}
+	if g.lk == nil {
+		g.lk = newG.lk
+	}
+	g.lk.Lock()
+	g.directed, g.vertices, g.out = newG.directed, newG.vertices, newG.out
+	g.lk.Unlock()
+	return nil
+}
+
+// MarshalBinary gob-encodes the graph, vertex types must be registered with
+// codec.Register.
+func (g *Graph) MarshalBinary() ([]byte, error) {
+	s, err := g.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return codec.MarshalBinary(s)
+}
+
+func (g *Graph) UnmarshalBinary(data []byte) error {
+	var s snapshot
+	if err := codec.UnmarshalBinary(data, &s); err != nil {
+		return err
+	}
+	return g.restore(s)
+}
+
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	s, err := g.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	vs, err := codec.WrapAll(s.Vertices)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonSnapshot{Directed: s.Directed, Vertices: vs, Edges: s.Edges})
+}
+
+func (g *Graph) UnmarshalJSON(data []byte) error {
+	var s jsonSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	vertices, err := codec.UnwrapAll(s.Vertices)
+	if err != nil {
+		return err
+	}
+	return g.restore(snapshot{Directed: s.Directed, Vertices: vertices, Edges: s.Edges})
+}
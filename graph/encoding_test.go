@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func TestGraph_MarshalBinary(t *testing.T) {
+	should := fGraph("MarshalBinary", t)
+	for _, directed := range []bool{true, false} {
+		should("round trip through gob", diamond(directed).String(), func() interface{} {
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(diamond(directed)); err != nil {
+				return err
+			}
+			g := NewDirected()
+			if err := gob.NewDecoder(&buf).Decode(g); err != nil {
+				return err
+			}
+			return g.String()
+		})
+	}
+	should("refuse edges to missing vertices", codec.ErrInvalidData, func() interface{} {
+		data, _ := codec.MarshalBinary(snapshot{Vertices: []interface{}{"a"}, Edges: []edgeRef{{From: 0, To: 1}}})
+		return New(true).UnmarshalBinary(data)
+	})
+}
+
+func TestGraph_MarshalJSON(t *testing.T) {
+	should := fGraph("MarshalJSON", t)
+	should("round trip keeping weights and vertex types", []interface{}{diamond(false).String(), 4.0, true}, func() interface{} {
+		g := diamond(false)
+		g.AddEdge("e", uint(1), 2)
+		data, err := json.Marshal(g)
+		if err != nil {
+			return err
+		}
+		var decoded Graph
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+		hasUint := decoded.HasVertex(uint(1))
+		decoded.RemoveVertex(uint(1))
+		w, _ := decoded.Weight("c", "a")
+		return []interface{}{decoded.String(), w, hasUint}
+	})
+}
+
+func TestGraph_snapshot(t *testing.T) {
+	should := fGraph("snapshot", t)
+	should("be consistent while the graph is mutated", true, func() interface{} {
+		g := New(true)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 500; i++ {
+				g.AddEdge(i, i+1, 1)
+				g.RemoveVertex(i - 1)
+			}
+		}()
+		for {
+			select {
+			case <-done:
+				return true
+			default:
+			}
+			s, err := g.snapshot()
+			if err != nil {
+				return err
+			}
+			for _, e := range s.Edges {
+				if e.From >= len(s.Vertices) || e.To >= len(s.Vertices) {
+					return false
+				}
+			}
+		}
+	})
+	should("refuse edges to vertices that cannot be looked up", []interface{}{true, true}, func() interface{} {
+		g := New(true)
+		g.AddEdge("a", math.NaN(), 1)
+		_, binErr := g.MarshalBinary()
+		_, jsonErr := g.MarshalJSON()
+		return []interface{}{errors.Is(binErr, codec.ErrInvalidData), errors.Is(jsonErr, codec.ErrInvalidData)}
+	})
+}
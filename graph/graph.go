@@ -0,0 +1,552 @@
+package graph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/nl253/DataStructures/iterator"
+	"github.com/nl253/DataStructures/list"
+	"github.com/nl253/DataStructures/stream"
+)
+
+var (
+	ErrCycle          = errors.New("graph: graph contains a cycle")
+	ErrUndirected     = errors.New("graph: operation requires a directed graph")
+	ErrNegativeWeight = errors.New("graph: negative edge weight")
+	ErrNoVertex       = errors.New("graph: no such vertex")
+)
+
+type Edge struct {
+	From   interface{}
+	To     interface{}
+	Weight float64
+}
+
+func (e *Edge) String() string {
+	return fmt.Sprintf("%v -(%v)-> %v", e.From, e.Weight, e.To)
+}
+
+type Graph struct {
+	directed bool
+	lk       *sync.RWMutex
+	vertices []interface{}
+	out      map[interface{}][]*Edge
+}
+
+func New(directed bool) *Graph {
+	return &Graph{
+		directed: directed,
+		lk:       &sync.RWMutex{},
+		vertices: make([]interface{}, 0),
+		out:      make(map[interface{}][]*Edge),
+	}
+}
+
+func NewDirected() *Graph { return New(true) }
+
+func NewUndirected() *Graph { return New(false) }
+
+func (g *Graph) Directed() bool { return g.directed }
+
+func (g *Graph) AddVertex(v interface{}) bool {
+	g.lk.Lock()
+	defer g.lk.Unlock()
+	return g.addVertex(v)
+}
+
+func (g *Graph) addVertex(v interface{}) bool {
+	if _, ok := g.out[v]; ok {
+		return false
+	}
+	g.vertices = append(g.vertices, v)
+	g.out[v] = make([]*Edge, 0)
+	return true
+}
+
+func (g *Graph) HasVertex(v interface{}) bool {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	_, ok := g.out[v]
+	return ok
+}
+
+func (g *Graph) RemoveVertex(v interface{}) bool {
+	g.lk.Lock()
+	defer g.lk.Unlock()
+	if _, ok := g.out[v]; !ok {
+		return false
+	}
+	delete(g.out, v)
+	for i, u := range g.vertices {
+		if u == v {
+			g.vertices = append(g.vertices[:i], g.vertices[i+1:]...)
+			break
+		}
+	}
+	for _, u := range g.vertices {
+		g.unlink(u, v)
+	}
+	return true
+}
+
+// AddEdge connects u to v, adding either vertex if missing. An existing edge
+// has its weight replaced.
+func (g *Graph) AddEdge(u interface{}, v interface{}, weight float64) {
+	g.lk.Lock()
+	defer g.lk.Unlock()
+	g.addVertex(u)
+	g.addVertex(v)
+	g.link(u, v, weight)
+	if !g.directed && u != v {
+		g.link(v, u, weight)
+	}
+}
+
+func (g *Graph) link(u interface{}, v interface{}, weight float64) {
+	for _, e := range g.out[u] {
+		if e.To == v {
+			e.Weight = weight
+			return
+		}
+	}
+	g.out[u] = append(g.out[u], &Edge{From: u, To: v, Weight: weight})
+}
+
+func (g *Graph) RemoveEdge(u interface{}, v interface{}) bool {
+	g.lk.Lock()
+	defer g.lk.Unlock()
+	ok := g.unlink(u, v)
+	if ok && !g.directed {
+		g.unlink(v, u)
+	}
+	return ok
+}
+
+func (g *Graph) unlink(u interface{}, v interface{}) bool {
+	es := g.out[u]
+	for i, e := range es {
+		if e.To == v {
+			g.out[u] = append(es[:i], es[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Graph) HasEdge(u interface{}, v interface{}) bool {
+	_, ok := g.Weight(u, v)
+	return ok
+}
+
+func (g *Graph) Weight(u interface{}, v interface{}) (float64, bool) {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	for _, e := range g.out[u] {
+		if e.To == v {
+			return e.Weight, true
+		}
+	}
+	return 0, false
+}
+
+func (g *Graph) Neighbours(v interface{}) *list.ConcurrentList {
+	xs := list.New()
+	for _, u := range g.neighbours(v) {
+		xs.PushBack(u)
+	}
+	return xs
+}
+
+func (g *Graph) neighbours(v interface{}) []interface{} {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	es := g.out[v]
+	vs := make([]interface{}, len(es))
+	for i, e := range es {
+		vs[i] = e.To
+	}
+	return vs
+}
+
+func (g *Graph) Vertices() *list.ConcurrentList {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	return list.New(g.vertices...)
+}
+
+// Edges lists a copy of every edge once, undirected edges are reported in
+// the direction they were first added.
+func (g *Graph) Edges() *list.ConcurrentList {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	xs := list.New()
+	for _, x := range g.edges() {
+		e := x.(*Edge)
+		xs.PushBack(&Edge{From: e.From, To: e.To, Weight: e.Weight})
+	}
+	return xs
+}
+
+func (g *Graph) edges() []interface{} {
+	xs := make([]interface{}, 0)
+	seen := make(map[*Edge]bool)
+	for _, u := range g.vertices {
+		for _, e := range g.out[u] {
+			if g.directed {
+				xs = append(xs, e)
+				continue
+			}
+			if back := g.reverse(e); back != nil && seen[back] {
+				continue
+			}
+			seen[e] = true
+			xs = append(xs, e)
+		}
+	}
+	return xs
+}
+
+func (g *Graph) reverse(e *Edge) *Edge {
+	for _, b := range g.out[e.To] {
+		if b.To == e.From {
+			return b
+		}
+	}
+	return nil
+}
+
+func (g *Graph) Order() uint {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	return uint(len(g.vertices))
+}
+
+func (g *Graph) Size() uint {
+	return g.Edges().Size()
+}
+
+// BFS lazily visits every vertex reachable from start in breadth-first order.
+func (g *Graph) BFS(start interface{}) *iterator.Iterator {
+	if !g.HasVertex(start) {
+		return iterator.FromClojure(func() interface{} { return iterator.EndOfIteration })
+	}
+	queue := []interface{}{start}
+	seen := map[interface{}]bool{start: true}
+	return iterator.FromClojure(func() interface{} {
+		if len(queue) == 0 {
+			return iterator.EndOfIteration
+		}
+		v := queue[0]
+		queue = queue[1:]
+		for _, u := range g.neighbours(v) {
+			if !seen[u] {
+				seen[u] = true
+				queue = append(queue, u)
+			}
+		}
+		return v
+	})
+}
+
+// DFS lazily visits every vertex reachable from start in depth-first
+// pre-order, neighbours are explored in the order their edges were added.
+func (g *Graph) DFS(start interface{}) *iterator.Iterator {
+	if !g.HasVertex(start) {
+		return iterator.FromClojure(func() interface{} { return iterator.EndOfIteration })
+	}
+	stack := []interface{}{start}
+	seen := make(map[interface{}]bool)
+	return iterator.FromClojure(func() interface{} {
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+			ns := g.neighbours(v)
+			for i := len(ns) - 1; i >= 0; i-- {
+				if !seen[ns[i]] {
+					stack = append(stack, ns[i])
+				}
+			}
+			return v
+		}
+		return iterator.EndOfIteration
+	})
+}
+
+func (g *Graph) BFSStream(start interface{}) *stream.Stream {
+	return toStream(g.BFS(start))
+}
+
+func (g *Graph) DFSStream(start interface{}) *stream.Stream {
+	return toStream(g.DFS(start))
+}
+
+func toStream(it *iterator.Iterator) *stream.Stream {
+	s := stream.New()
+	go func() {
+		for x := it.Pull(); x != iterator.EndOfIteration; x = it.Pull() {
+			s.PushBack(x)
+		}
+		s.Close()
+	}()
+	return s
+}
+
+// TopologicalSort orders the vertices so that every edge points forward
+// using Kahn's algorithm. Ties are broken by insertion order.
+func (g *Graph) TopologicalSort() (*list.ConcurrentList, error) {
+	if !g.directed {
+		return nil, ErrUndirected
+	}
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	inDeg := make(map[interface{}]int, len(g.vertices))
+	for _, v := range g.vertices {
+		for _, e := range g.out[v] {
+			inDeg[e.To]++
+		}
+	}
+	queue := make([]interface{}, 0)
+	for _, v := range g.vertices {
+		if inDeg[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+	sorted := list.New()
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		sorted.PushBack(v)
+		for _, e := range g.out[v] {
+			if inDeg[e.To]--; inDeg[e.To] == 0 {
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	if sorted.Size() != uint(len(g.vertices)) {
+		return nil, ErrCycle
+	}
+	return sorted, nil
+}
+
+func (g *Graph) HasCycle() bool {
+	if g.directed {
+		_, err := g.TopologicalSort()
+		return err == ErrCycle
+	}
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	uf := make(map[interface{}]interface{})
+	var find func(interface{}) interface{}
+	find = func(v interface{}) interface{} {
+		if p, ok := uf[v]; ok && p != v {
+			uf[v] = find(p)
+			return uf[v]
+		}
+		return v
+	}
+	seen := make(map[*Edge]bool)
+	for _, u := range g.vertices {
+		for _, e := range g.out[u] {
+			if back := g.reverse(e); back != nil && seen[back] {
+				continue
+			}
+			seen[e] = true
+			a, b := find(e.From), find(e.To)
+			if a == b {
+				return true
+			}
+			uf[a] = b
+		}
+	}
+	return false
+}
+
+type Paths struct {
+	Source interface{}
+	dist   map[interface{}]float64
+	prev   map[interface{}]interface{}
+}
+
+func (p *Paths) Dist(v interface{}) (float64, bool) {
+	d, ok := p.dist[v]
+	return d, ok
+}
+
+func (p *Paths) Reachable(v interface{}) bool {
+	_, ok := p.dist[v]
+	return ok
+}
+
+// PathTo lists the vertices on the shortest path from the source to v, or
+// returns an empty list when v is unreachable.
+func (p *Paths) PathTo(v interface{}) *list.ConcurrentList {
+	path := list.New()
+	if !p.Reachable(v) {
+		return path
+	}
+	for focus := v; focus != p.Source; focus = p.prev[focus] {
+		path.PushFront(focus)
+	}
+	path.PushFront(p.Source)
+	return path
+}
+
+// Dijkstra computes shortest paths from src to every reachable vertex.
+func (g *Graph) Dijkstra(src interface{}) (*Paths, error) {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	if _, ok := g.out[src]; !ok {
+		return nil, ErrNoVertex
+	}
+	for _, es := range g.out {
+		for _, e := range es {
+			if e.Weight < 0 {
+				return nil, ErrNegativeWeight
+			}
+		}
+	}
+	paths := &Paths{
+		Source: src,
+		dist:   map[interface{}]float64{src: 0},
+		prev:   make(map[interface{}]interface{}),
+	}
+	done := make(map[interface{}]bool)
+	pq := &distHeap{{v: src, dist: 0}}
+	for pq.Len() > 0 {
+		top := heap.Pop(pq).(*distItem)
+		if done[top.v] {
+			continue
+		}
+		done[top.v] = true
+		for _, e := range g.out[top.v] {
+			alt := top.dist + e.Weight
+			if d, ok := paths.dist[e.To]; !ok || alt < d {
+				paths.dist[e.To] = alt
+				paths.prev[e.To] = top.v
+				heap.Push(pq, &distItem{v: e.To, dist: alt})
+			}
+		}
+	}
+	return paths, nil
+}
+
+func (g *Graph) ShortestPath(src interface{}, dst interface{}) (*list.ConcurrentList, float64, bool) {
+	paths, err := g.Dijkstra(src)
+	if err != nil || !paths.Reachable(dst) {
+		return list.New(), math.Inf(1), false
+	}
+	d, _ := paths.Dist(dst)
+	return paths.PathTo(dst), d, true
+}
+
+type distItem struct {
+	v    interface{}
+	dist float64
+}
+
+type distHeap []*distItem
+
+func (h distHeap) Len() int { return len(h) }
+
+func (h distHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+
+func (h distHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *distHeap) Push(x interface{}) { *h = append(*h, x.(*distItem)) }
+
+func (h *distHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// ConnectedComponents groups vertices into lists of mutually reachable
+// vertices. Directed graphs are treated as undirected (weak connectivity).
+func (g *Graph) ConnectedComponents() *list.ConcurrentList {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	undirected := make(map[interface{}][]interface{}, len(g.vertices))
+	for _, u := range g.vertices {
+		for _, e := range g.out[u] {
+			undirected[u] = append(undirected[u], e.To)
+			if g.directed {
+				undirected[e.To] = append(undirected[e.To], u)
+			}
+		}
+	}
+	components := list.New()
+	seen := make(map[interface{}]bool)
+	for _, v := range g.vertices {
+		if seen[v] {
+			continue
+		}
+		component := list.New()
+		seen[v] = true
+		queue := []interface{}{v}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			component.PushBack(u)
+			for _, w := range undirected[u] {
+				if !seen[w] {
+					seen[w] = true
+					queue = append(queue, w)
+				}
+			}
+		}
+		components.PushBack(component)
+	}
+	return components
+}
+
+func (g *Graph) Clone() *Graph {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	newG := New(g.directed)
+	for _, v := range g.vertices {
+		newG.addVertex(v)
+	}
+	for _, v := range g.vertices {
+		for _, e := range g.out[v] {
+			newG.out[v] = append(newG.out[v], &Edge{From: e.From, To: e.To, Weight: e.Weight})
+		}
+	}
+	return newG
+}
+
+func (g *Graph) Eq(x interface{}) bool {
+	switch x.(type) {
+	case *Graph:
+		return g == x.(*Graph)
+	default:
+		return false
+	}
+}
+
+func (g *Graph) String() string {
+	g.lk.RLock()
+	defer g.lk.RUnlock()
+	arrow := "--"
+	if g.directed {
+		arrow = "->"
+	}
+	parts := make([]string, len(g.vertices))
+	for i, v := range g.vertices {
+		ns := make([]string, len(g.out[v]))
+		for j, e := range g.out[v] {
+			ns[j] = fmt.Sprintf("%v", e.To)
+		}
+		parts[i] = fmt.Sprintf("%v %s [%s]", v, arrow, strings.Join(ns, " "))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(parts, ", "))
+}
@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/nl253/DataStructures/iterator"
+	"github.com/nl253/DataStructures/list"
+	ut "github.com/nl253/Testing"
+)
+
+var fGraph = ut.Test("Graph")
+
+// diamond is a -> b, a -> c, b -> d, c -> d plus an isolated vertex e.
+func diamond(directed bool) *Graph {
+	g := New(directed)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 4)
+	g.AddEdge("b", "d", 5)
+	g.AddEdge("c", "d", 1)
+	g.AddVertex("e")
+	return g
+}
+
+func TestGraph_AddEdge(t *testing.T) {
+	should := fGraph("AddEdge", t)
+	should("add missing vertices", uint(5), func() interface{} { return diamond(true).Order() })
+	should("count each undirected edge once", uint(4), func() interface{} { return diamond(false).Size() })
+	should("be one-way in directed graphs", []bool{true, false}, func() interface{} {
+		g := diamond(true)
+		return []bool{g.HasEdge("a", "b"), g.HasEdge("b", "a")}
+	})
+	should("be two-way in undirected graphs", []bool{true, true}, func() interface{} {
+		g := diamond(false)
+		return []bool{g.HasEdge("a", "b"), g.HasEdge("b", "a")}
+	})
+	should("replace the weight of existing edges", 7.0, func() interface{} {
+		g := diamond(true)
+		g.AddEdge("a", "b", 7)
+		w, _ := g.Weight("a", "b")
+		return w
+	})
+}
+
+func TestGraph_RemoveVertex(t *testing.T) {
+	should := fGraph("RemoveVertex", t)
+	should("remove incident edges", list.New("c"), func() interface{} {
+		g := diamond(true)
+		g.RemoveVertex("b")
+		return g.Neighbours("a")
+	})
+}
+
+func TestGraph_Edges(t *testing.T) {
+	should := fGraph("Edges", t)
+	should("list undirected edges once", uint(4), func() interface{} { return diamond(false).Edges().Size() })
+	should("hand out copies", []interface{}{1.0, 1.0, true}, func() interface{} {
+		g := diamond(false)
+		e := g.Edges().Nth(0).(*Edge)
+		e.To, e.Weight = "zzz", 9
+		ab, _ := g.Weight("a", "b")
+		ba, _ := g.Weight("b", "a")
+		return []interface{}{ab, ba, g.HasEdge("a", "b")}
+	})
+}
+
+func TestGraph_BFS(t *testing.T) {
+	should := fGraph("BFS", t)
+	should("visit in breadth-first order", list.New("a", "b", "c", "d"), func() interface{} {
+		return diamond(true).BFS("a").PullAll().TakeWhile(func(x interface{}) bool { return x != iterator.EndOfIteration })
+	})
+	should("visit nothing from a missing vertex", iterator.EndOfIteration, func() interface{} {
+		return diamond(true).BFS("z").Pull()
+	})
+	should("stream vertices", list.New("a", "b", "c", "d"), func() interface{} {
+		return diamond(true).BFSStream("a").PullAll()
+	})
+}
+
+func TestGraph_DFS(t *testing.T) {
+	should := fGraph("DFS", t)
+	should("visit in depth-first order", list.New("a", "b", "d", "c"), func() interface{} {
+		return diamond(false).DFS("a").Take(4).PullAll().TakeWhile(func(x interface{}) bool { return x != iterator.EndOfIteration })
+	})
+	should("stream vertices", list.New("d"), func() interface{} {
+		return diamond(true).DFSStream("d").PullAll()
+	})
+}
+
+func TestGraph_TopologicalSort(t *testing.T) {
+	should := fGraph("TopologicalSort", t)
+	should("order a DAG", list.New("a", "e", "b", "c", "d"), func() interface{} {
+		sorted, _ := diamond(true).TopologicalSort()
+		return sorted
+	})
+	should("detect cycles", ErrCycle, func() interface{} {
+		g := diamond(true)
+		g.AddEdge("d", "a", 1)
+		_, err := g.TopologicalSort()
+		return err
+	})
+	should("refuse undirected graphs", ErrUndirected, func() interface{} {
+		_, err := diamond(false).TopologicalSort()
+		return err
+	})
+}
+
+func TestGraph_HasCycle(t *testing.T) {
+	should := fGraph("HasCycle", t)
+	should("find the cycle in the undirected diamond", true, func() interface{} { return diamond(false).HasCycle() })
+	should("not find a cycle in the directed diamond", false, func() interface{} { return diamond(true).HasCycle() })
+	should("not find a cycle in an undirected tree", false, func() interface{} {
+		g := NewUndirected()
+		g.AddEdge(1, 2, 1)
+		g.AddEdge(1, 3, 1)
+		return g.HasCycle()
+	})
+}
+
+func TestGraph_Dijkstra(t *testing.T) {
+	should := fGraph("Dijkstra", t)
+	should("find the cheapest path", []interface{}{list.New("a", "c", "d"), 5.0, true}, func() interface{} {
+		path, d, ok := diamond(true).ShortestPath("a", "d")
+		return []interface{}{path, d, ok}
+	})
+	should("report unreachable vertices", false, func() interface{} {
+		_, _, ok := diamond(true).ShortestPath("a", "e")
+		return ok
+	})
+	should("refuse negative weights", ErrNegativeWeight, func() interface{} {
+		g := diamond(true)
+		g.AddEdge("d", "e", -1)
+		_, err := g.Dijkstra("a")
+		return err
+	})
+}
+
+func TestGraph_ConnectedComponents(t *testing.T) {
+	should := fGraph("ConnectedComponents", t)
+	should("group reachable vertices", "[[a b c d] [e]]", func() interface{} {
+		return diamond(true).ConnectedComponents().String()
+	})
+}
@@ -0,0 +1,28 @@
+package hyperloglog
+
+import (
+	"encoding/json"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func init() {
+	codec.Register("hyperloglog.HyperLogLog", &HyperLogLog{})
+}
+
+// MarshalJSON writes the binary form as a base64 string.
+func (h *HyperLogLog) MarshalJSON() ([]byte, error) {
+	data, err := h.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+func (h *HyperLogLog) UnmarshalJSON(data []byte) error {
+	var raw []byte
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return h.UnmarshalBinary(raw)
+}
@@ -0,0 +1,40 @@
+package hyperloglog
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestHyperLogLog_MarshalJSON(t *testing.T) {
+	should := fHLL("MarshalJSON", t)
+	should("round trip", true, func() interface{} {
+		h := New(10)
+		for i := 0; i < 1000; i++ {
+			h.Add(i)
+		}
+		data, err := json.Marshal(h)
+		if err != nil {
+			return err
+		}
+		var decoded HyperLogLog
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+		return h.Eq(&decoded)
+	})
+	should("round trip through gob", true, func() interface{} {
+		h := New(8)
+		h.Add("x")
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(h); err != nil {
+			return err
+		}
+		decoded := New(4)
+		if err := gob.NewDecoder(&buf).Decode(decoded); err != nil {
+			return err
+		}
+		return h.Eq(decoded)
+	})
+}
@@ -0,0 +1,236 @@
+package hyperloglog
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+const (
+	MinPrecision uint8 = 4
+	MaxPrecision uint8 = 18
+	version      byte  = 1
+)
+
+var (
+	ErrPrecisionMismatch = errors.New("hyperloglog: cannot merge sketches of different precision")
+	ErrInvalidData       = errors.New("hyperloglog: invalid serialized data")
+)
+
+type HyperLogLog struct {
+	p    uint8
+	regs []uint8
+	lk   *sync.RWMutex
+}
+
+func New(precision uint8) *HyperLogLog {
+	if precision < MinPrecision || precision > MaxPrecision {
+		panic(fmt.Sprintf("[ERROR] precision must be in [%d, %d] but was %d", MinPrecision, MaxPrecision, precision))
+	}
+	return &HyperLogLog{
+		p:    precision,
+		regs: make([]uint8, 1<<precision),
+		lk:   &sync.RWMutex{},
+	}
+}
+
+// Reducer folds x into the *HyperLogLog acc, so a sketch can be built with
+// Stream.Reduce or Iterator.ReduceAll.
+func Reducer(acc interface{}, x interface{}) interface{} {
+	acc.(*HyperLogLog).Add(x)
+	return acc
+}
+
+func (h *HyperLogLog) Add(x interface{}) {
+	h.AddHash(hash(x))
+}
+
+func (h *HyperLogLog) AddHash(x uint64) {
+	idx := x >> (64 - h.p)
+	w := x<<h.p | 1<<(h.p-1)
+	rho := uint8(bits.LeadingZeros64(w)) + 1
+	h.lk.Lock()
+	if rho > h.regs[idx] {
+		h.regs[idx] = rho
+	}
+	h.lk.Unlock()
+}
+
+func (h *HyperLogLog) Count() uint64 {
+	h.lk.RLock()
+	defer h.lk.RUnlock()
+	m := float64(len(h.regs))
+	sum := 0.0
+	zeros := 0
+	for _, r := range h.regs {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	est := alpha(len(h.regs)) * m * m / sum
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/float64(zeros))
+	}
+	return uint64(est + 0.5)
+}
+
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h == other {
+		return nil
+	}
+	if h.p != other.p {
+		return ErrPrecisionMismatch
+	}
+	other.lk.RLock()
+	regs := make([]uint8, len(other.regs))
+	copy(regs, other.regs)
+	other.lk.RUnlock()
+	h.lk.Lock()
+	for i, r := range regs {
+		if r > h.regs[i] {
+			h.regs[i] = r
+		}
+	}
+	h.lk.Unlock()
+	return nil
+}
+
+func (h *HyperLogLog) Precision() uint8 { return h.p }
+
+func (h *HyperLogLog) Clear() {
+	h.lk.Lock()
+	for i := range h.regs {
+		h.regs[i] = 0
+	}
+	h.lk.Unlock()
+}
+
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	h.lk.RLock()
+	defer h.lk.RUnlock()
+	data := make([]byte, 2+len(h.regs))
+	data[0] = version
+	data[1] = h.p
+	copy(data[2:], h.regs)
+	return data, nil
+}
+
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != version {
+		return ErrInvalidData
+	}
+	p := data[1]
+	if p < MinPrecision || p > MaxPrecision || len(data) != 2+(1<<p) {
+		return ErrInvalidData
+	}
+	regs := make([]uint8, 1<<p)
+	copy(regs, data[2:])
+	if h.lk == nil {
+		h.lk = &sync.RWMutex{}
+	}
+	h.lk.Lock()
+	h.p = p
+	h.regs = regs
+	h.lk.Unlock()
+	return nil
+}
+
+func (h *HyperLogLog) Clone() *HyperLogLog {
+	h.lk.RLock()
+	defer h.lk.RUnlock()
+	regs := make([]uint8, len(h.regs))
+	copy(regs, h.regs)
+	return &HyperLogLog{p: h.p, regs: regs, lk: &sync.RWMutex{}}
+}
+
+func (h *HyperLogLog) Eq(x interface{}) bool {
+	switch x.(type) {
+	case *HyperLogLog:
+		other := x.(*HyperLogLog)
+		if h == other {
+			return true
+		}
+		if h.p != other.p {
+			return false
+		}
+		h.lk.RLock()
+		defer h.lk.RUnlock()
+		other.lk.RLock()
+		defer other.lk.RUnlock()
+		for i, r := range h.regs {
+			if other.regs[i] != r {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func (h *HyperLogLog) String() string {
+	return fmt.Sprintf("HyperLogLog(p=%d, ~%d)", h.p, h.Count())
+}
+
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// hash prefixes every value with a tag for its type, so that equal bits of
+// different types, such as int(1) and uint(1) or "a" and []byte("a"), count
+// as distinct elements.
+func hash(x interface{}) uint64 {
+	h := fnv.New64a()
+	buf := make([]byte, 9)
+	word := func(tag byte, v uint64) {
+		buf[0] = tag
+		binary.LittleEndian.PutUint64(buf[1:], v)
+		_, _ = h.Write(buf)
+	}
+	switch x.(type) {
+	case string:
+		_, _ = h.Write([]byte{'s'})
+		_, _ = h.Write([]byte(x.(string)))
+	case []byte:
+		_, _ = h.Write([]byte{'b'})
+		_, _ = h.Write(x.([]byte))
+	case int:
+		word('i', uint64(x.(int)))
+	case int64:
+		word('I', uint64(x.(int64)))
+	case uint:
+		word('u', uint64(x.(uint)))
+	case uint64:
+		word('U', x.(uint64))
+	case float64:
+		word('f', math.Float64bits(x.(float64)))
+	default:
+		_, _ = fmt.Fprintf(h, "%T:%v", x, x)
+	}
+	return mix(h.Sum64())
+}
+
+// mix is the splitmix64 finalizer, FNV alone leaves the high bits (used for
+// the register index) poorly distributed for short keys.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,96 @@
+package hyperloglog
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	ut "github.com/nl253/Testing"
+)
+
+var fHLL = ut.Test("HyperLogLog")
+
+func withinErr(est uint64, actual uint64, tolerance float64) bool {
+	return math.Abs(float64(est)-float64(actual)) <= tolerance*float64(actual)
+}
+
+func TestHyperLogLog_Count(t *testing.T) {
+	should := fHLL("Count", t)
+	should("estimate 0 for an empty sketch", uint64(0), func() interface{} {
+		return New(14).Count()
+	})
+	should("count small sets exactly", uint64(3), func() interface{} {
+		h := New(14)
+		for _, x := range []interface{}{"a", "b", "c", "a", "b"} {
+			h.Add(x)
+		}
+		return h.Count()
+	})
+	should("tell values of different types apart", uint64(7), func() interface{} {
+		h := New(14)
+		for _, x := range []interface{}{1, int64(1), uint(1), uint64(1), "a", []byte("a"), "1"} {
+			h.Add(x)
+		}
+		return h.Count()
+	})
+	for _, n := range []uint64{1000, 100000} {
+		should(fmt.Sprintf("estimate %d distinct ints within 2%%", n), true, func() interface{} {
+			h := New(14)
+			for i := uint64(0); i < n; i++ {
+				h.Add(int(i))
+				h.Add(int(i))
+			}
+			return withinErr(h.Count(), n, 0.02)
+		})
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	should := fHLL("Merge", t)
+	should("estimate the union", true, func() interface{} {
+		h1 := New(12)
+		h2 := New(12)
+		for i := 0; i < 5000; i++ {
+			h1.Add(i)
+			h2.Add(i + 2500)
+		}
+		if err := h1.Merge(h2); err != nil {
+			return false
+		}
+		return withinErr(h1.Count(), 7500, 0.05)
+	})
+	should("refuse sketches of different precision", ErrPrecisionMismatch, func() interface{} {
+		return New(12).Merge(New(10))
+	})
+}
+
+func TestHyperLogLog_MarshalBinary(t *testing.T) {
+	should := fHLL("MarshalBinary", t)
+	should("round-trip through UnmarshalBinary", true, func() interface{} {
+		h := New(10)
+		for i := 0; i < 1000; i++ {
+			h.Add(fmt.Sprintf("x%d", i))
+		}
+		data, _ := h.MarshalBinary()
+		other := &HyperLogLog{}
+		if err := other.UnmarshalBinary(data); err != nil {
+			return false
+		}
+		return other.Eq(h) && other.Count() == h.Count()
+	})
+	should("reject truncated data", ErrInvalidData, func() interface{} {
+		data, _ := New(10).MarshalBinary()
+		return (&HyperLogLog{}).UnmarshalBinary(data[:10])
+	})
+}
+
+func TestHyperLogLog_Reducer(t *testing.T) {
+	should := fHLL("Reducer", t)
+	should("add each item to the accumulator", uint64(2), func() interface{} {
+		acc := interface{}(New(8))
+		for _, x := range []interface{}{1, 2, 1} {
+			acc = Reducer(acc, x)
+		}
+		return acc.(*HyperLogLog).Count()
+	})
+}
@@ -0,0 +1,102 @@
+package interval
+
+import (
+	"encoding/json"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func init() {
+	codec.Register("interval.Interval", Interval{})
+	codec.Register("interval.Tree", &Tree{})
+}
+
+type jsonInterval struct {
+	Low  float64      `json:"low"`
+	High float64      `json:"high"`
+	Val  *codec.Value `json:"val,omitempty"`
+}
+
+// MarshalJSON keeps the type of Val, which must be registered with
+// codec.Register.
+func (iv Interval) MarshalJSON() ([]byte, error) {
+	j := jsonInterval{Low: iv.Low, High: iv.High}
+	if iv.Val != nil {
+		v, err := codec.Wrap(iv.Val)
+		if err != nil {
+			return nil, err
+		}
+		j.Val = &v
+	}
+	return json.Marshal(j)
+}
+
+func (iv *Interval) UnmarshalJSON(data []byte) error {
+	var j jsonInterval
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	var val interface{}
+	if j.Val != nil {
+		x, err := j.Val.Unwrap()
+		if err != nil {
+			return err
+		}
+		val = x
+	}
+	*iv = Interval{Low: j.Low, High: j.High, Val: val}
+	return nil
+}
+
+type snapshot struct {
+	Intervals []Interval
+}
+
+func (t *Tree) snapshot() []Interval {
+	ivs := make([]Interval, 0)
+	t.Intervals().ForEach(func(x interface{}, _ uint) { ivs = append(ivs, x.(Interval)) })
+	return ivs
+}
+
+// restore rebuilds t from ivs, t may be the zero value.
+func (t *Tree) restore(ivs []Interval) error {
+	for _, iv := range ivs {
+		if iv.Low > iv.High {
+			return codec.ErrInvalidData
+		}
+	}
+	newT := New(ivs...)
+	if t.lk == nil {
+		t.lk = newT.lk
+	}
+	t.lk.Lock()
+	t.root, t.size, t.seq = newT.root, newT.size, newT.seq
+	t.lk.Unlock()
+	return nil
+}
+
+// MarshalBinary gob-encodes the intervals in order, payload types must be
+// registered with codec.Register.
+func (t *Tree) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary(snapshot{Intervals: t.snapshot()})
+}
+
+func (t *Tree) UnmarshalBinary(data []byte) error {
+	var s snapshot
+	if err := codec.UnmarshalBinary(data, &s); err != nil {
+		return err
+	}
+	return t.restore(s.Intervals)
+}
+
+func (t *Tree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.snapshot())
+}
+
+func (t *Tree) UnmarshalJSON(data []byte) error {
+	var ivs []Interval
+	if err := json.Unmarshal(data, &ivs); err != nil {
+		return err
+	}
+	return t.restore(ivs)
+}
@@ -0,0 +1,48 @@
+package interval
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func sample() *Tree {
+	return New(Interval{Low: 1, High: 5, Val: "a"}, Interval{Low: 0, High: 2, Val: 7}, Interval{Low: 3, High: 3})
+}
+
+func TestTree_MarshalBinary(t *testing.T) {
+	should := fTree("MarshalBinary", t)
+	should("round trip through gob", []interface{}{sample().String(), true}, func() interface{} {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(sample()); err != nil {
+			return err
+		}
+		var tree Tree
+		if err := gob.NewDecoder(&buf).Decode(&tree); err != nil {
+			return err
+		}
+		return []interface{}{tree.String(), isValid(&tree)}
+	})
+	should("refuse inverted intervals", codec.ErrInvalidData, func() interface{} {
+		data, _ := codec.MarshalBinary(snapshot{Intervals: []Interval{{Low: 2, High: 1}}})
+		return New().UnmarshalBinary(data)
+	})
+}
+
+func TestTree_MarshalJSON(t *testing.T) {
+	should := fTree("MarshalJSON", t)
+	should("round trip keeping payload types", []interface{}{sample().String(), true}, func() interface{} {
+		data, err := json.Marshal(sample())
+		if err != nil {
+			return err
+		}
+		tree := New()
+		if err := json.Unmarshal(data, tree); err != nil {
+			return err
+		}
+		return []interface{}{tree.String(), tree.Delete(Interval{Low: 0, High: 2, Val: 7})}
+	})
+}
@@ -0,0 +1,360 @@
+package interval
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/nl253/DataStructures"
+	"github.com/nl253/DataStructures/iterator"
+	"github.com/nl253/DataStructures/list"
+)
+
+// Interval is the closed range [Low, High] with an optional payload.
+type Interval struct {
+	Low  float64
+	High float64
+	Val  interface{}
+}
+
+func (iv Interval) Overlaps(low float64, high float64) bool {
+	return iv.Low <= high && low <= iv.High
+}
+
+func (iv Interval) Contains(point float64) bool {
+	return iv.Low <= point && point <= iv.High
+}
+
+func (iv Interval) String() string {
+	if iv.Val == nil {
+		return fmt.Sprintf("[%v, %v]", iv.Low, iv.High)
+	}
+	return fmt.Sprintf("[%v, %v] %v", iv.Low, iv.High, iv.Val)
+}
+
+// Tree is an AVL tree ordered by Low where every node also tracks the
+// largest High in its subtree, so overlap queries can skip whole branches.
+type Tree struct {
+	root *node
+	size uint
+	seq  uint64
+	lk   *sync.RWMutex
+}
+
+type node struct {
+	iv     Interval
+	seq    uint64
+	max    float64
+	height int
+	left   *node
+	right  *node
+}
+
+func New(ivs ...Interval) *Tree {
+	t := &Tree{lk: &sync.RWMutex{}}
+	for _, iv := range ivs {
+		t.Insert(iv)
+	}
+	return t
+}
+
+func (t *Tree) Insert(iv Interval) {
+	if iv.Low > iv.High {
+		panic(fmt.Sprintf("[ERROR] interval low bound %v is greater than high bound %v", iv.Low, iv.High))
+	}
+	t.lk.Lock()
+	t.seq++
+	t.root = insert(t.root, &node{iv: iv, seq: t.seq, max: iv.High, height: 1})
+	t.size++
+	t.lk.Unlock()
+}
+
+// Delete removes one interval equal to iv (bounds and payload) and reports
+// whether there was one.
+func (t *Tree) Delete(iv Interval) bool {
+	t.lk.Lock()
+	defer t.lk.Unlock()
+	target := find(t.root, iv)
+	if target == nil {
+		return false
+	}
+	t.root = remove(t.root, target.iv, target.seq)
+	t.size--
+	return true
+}
+
+// Overlapping lazily iterates, in order of Low, over intervals intersecting
+// [low, high]. Each Pull looks up the match following the previous one under
+// a read lock, so changes made to the tree while iterating are seen if they
+// come after the position reached.
+func (t *Tree) Overlapping(low float64, high float64) *iterator.Iterator {
+	var last *node
+	return iterator.FromClojure(func() interface{} {
+		t.lk.RLock()
+		defer t.lk.RUnlock()
+		n := nextOverlapping(t.root, low, high, last)
+		if n == nil {
+			return iterator.EndOfIteration
+		}
+		// Nodes are reused when the tree changes, keep a copy of the position.
+		last = &node{iv: n.iv, seq: n.seq}
+		return n.iv
+	})
+}
+
+func (t *Tree) Stab(point float64) *iterator.Iterator {
+	return t.Overlapping(point, point)
+}
+
+func (t *Tree) AnyOverlapping(low float64, high float64) bool {
+	t.lk.RLock()
+	defer t.lk.RUnlock()
+	for focus := t.root; focus != nil; {
+		if focus.iv.Overlaps(low, high) {
+			return true
+		}
+		if focus.left != nil && focus.left.max >= low {
+			focus = focus.left
+		} else {
+			focus = focus.right
+		}
+	}
+	return false
+}
+
+// Merge coalesces overlapping intervals into a list of disjoint intervals
+// without payloads, sorted by Low. The tree itself is not modified.
+func (t *Tree) Merge() *list.ConcurrentList {
+	merged := list.New()
+	t.lk.RLock()
+	ivs := make([]Interval, 0, t.size)
+	inOrder(t.root, func(n *node) { ivs = append(ivs, n.iv) })
+	t.lk.RUnlock()
+	if len(ivs) == 0 {
+		return merged
+	}
+	acc := Interval{Low: ivs[0].Low, High: ivs[0].High}
+	for _, iv := range ivs[1:] {
+		if iv.Low <= acc.High {
+			acc.High = math.Max(acc.High, iv.High)
+			continue
+		}
+		merged.PushBack(acc)
+		acc = Interval{Low: iv.Low, High: iv.High}
+	}
+	merged.PushBack(acc)
+	return merged
+}
+
+func (t *Tree) Intervals() *list.ConcurrentList {
+	xs := list.New()
+	t.lk.RLock()
+	defer t.lk.RUnlock()
+	inOrder(t.root, func(n *node) { xs.PushBack(n.iv) })
+	return xs
+}
+
+func (t *Tree) Size() uint {
+	t.lk.RLock()
+	defer t.lk.RUnlock()
+	return t.size
+}
+
+func (t *Tree) Empty() bool { return t.Size() == 0 }
+
+func (t *Tree) Clear() {
+	t.lk.Lock()
+	t.root = nil
+	t.size = 0
+	t.lk.Unlock()
+}
+
+func (t *Tree) Clone() *Tree {
+	newT := New()
+	t.Intervals().ForEach(func(x interface{}, _ uint) { newT.Insert(x.(Interval)) })
+	return newT
+}
+
+func (t *Tree) Eq(x interface{}) bool {
+	switch x.(type) {
+	case *Tree:
+		return t == x.(*Tree)
+	default:
+		return false
+	}
+}
+
+func (t *Tree) String() string {
+	parts := make([]string, 0)
+	t.Intervals().ForEach(func(x interface{}, _ uint) { parts = append(parts, x.(Interval).String()) })
+	return fmt.Sprintf("{%s}", strings.Join(parts, " "))
+}
+
+func less(iv Interval, seq uint64, n *node) bool {
+	if iv.Low != n.iv.Low {
+		return iv.Low < n.iv.Low
+	}
+	if iv.High != n.iv.High {
+		return iv.High < n.iv.High
+	}
+	return seq < n.seq
+}
+
+func height(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func update(n *node) {
+	n.height = 1 + maxInt(height(n.left), height(n.right))
+	n.max = n.iv.High
+	if n.left != nil && n.left.max > n.max {
+		n.max = n.left.max
+	}
+	if n.right != nil && n.right.max > n.max {
+		n.max = n.right.max
+	}
+}
+
+func rotateRight(n *node) *node {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	update(n)
+	update(l)
+	return l
+}
+
+func rotateLeft(n *node) *node {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	update(n)
+	update(r)
+	return r
+}
+
+func balance(n *node) *node {
+	update(n)
+	switch bf := height(n.left) - height(n.right); {
+	case bf > 1:
+		if height(n.left.left) < height(n.left.right) {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	case bf < -1:
+		if height(n.right.right) < height(n.right.left) {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	default:
+		return n
+	}
+}
+
+func insert(n *node, newNode *node) *node {
+	if n == nil {
+		return newNode
+	}
+	if less(newNode.iv, newNode.seq, n) {
+		n.left = insert(n.left, newNode)
+	} else {
+		n.right = insert(n.right, newNode)
+	}
+	return balance(n)
+}
+
+func remove(n *node, iv Interval, seq uint64) *node {
+	if n == nil {
+		return nil
+	}
+	switch {
+	case n.seq == seq:
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		succ := n.right
+		for succ.left != nil {
+			succ = succ.left
+		}
+		n.right = remove(n.right, succ.iv, succ.seq)
+		n.iv = succ.iv
+		n.seq = succ.seq
+	case less(iv, seq, n):
+		n.left = remove(n.left, iv, seq)
+	default:
+		n.right = remove(n.right, iv, seq)
+	}
+	return balance(n)
+}
+
+func find(n *node, iv Interval) *node {
+	if n == nil || n.max < iv.High {
+		return nil
+	}
+	if n.iv.Low == iv.Low && n.iv.High == iv.High && sameVal(n.iv.Val, iv.Val) {
+		return n
+	}
+	if iv.Low <= n.iv.Low {
+		if found := find(n.left, iv); found != nil {
+			return found
+		}
+	}
+	if iv.Low >= n.iv.Low {
+		return find(n.right, iv)
+	}
+	return nil
+}
+
+// sameVal compares payloads with Eq when they are Equatable and with
+// reflect.DeepEqual otherwise, so slices, maps and funcs do not panic.
+func sameVal(x interface{}, y interface{}) bool {
+	if _, ok := x.(DataStructures.Equatable); ok {
+		return DataStructures.Eq(x, y)
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// nextOverlapping finds the first node in order after the position of last,
+// or the first one at all when last is nil, that overlaps [low, high].
+func nextOverlapping(n *node, low float64, high float64, last *node) *node {
+	if n == nil || n.max < low {
+		return nil
+	}
+	if last != nil && !less(last.iv, last.seq, n) {
+		return nextOverlapping(n.right, low, high, last)
+	}
+	if found := nextOverlapping(n.left, low, high, last); found != nil {
+		return found
+	}
+	if n.iv.Low > high {
+		return nil
+	}
+	if n.iv.Overlaps(low, high) {
+		return n
+	}
+	return nextOverlapping(n.right, low, high, last)
+}
+
+func inOrder(n *node, f func(*node)) {
+	if n == nil {
+		return
+	}
+	inOrder(n.left, f)
+	f(n)
+	inOrder(n.right, f)
+}
+
+func maxInt(x int, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,177 @@
+package interval
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/nl253/DataStructures/iterator"
+	"github.com/nl253/DataStructures/list"
+	ut "github.com/nl253/Testing"
+)
+
+const MANY uint = 1000
+
+var fTree = ut.Test("Tree")
+
+func isValid(t *Tree) bool {
+	var check func(n *node) (int, float64, uint, bool)
+	check = func(n *node) (int, float64, uint, bool) {
+		if n == nil {
+			return 0, 0, 0, true
+		}
+		lh, lmax, lsize, lok := check(n.left)
+		rh, rmax, rsize, rok := check(n.right)
+		max := n.iv.High
+		if n.left != nil && lmax > max {
+			max = lmax
+		}
+		if n.right != nil && rmax > max {
+			max = rmax
+		}
+		if n.max != max {
+			fmt.Printf("node %v has max %v but subtree max is %v\n", n.iv, n.max, max)
+			return 0, 0, 0, false
+		}
+		if d := lh - rh; d > 1 || d < -1 {
+			fmt.Printf("node %v is unbalanced (%d vs %d)\n", n.iv, lh, rh)
+			return 0, 0, 0, false
+		}
+		return 1 + maxInt(lh, rh), max, lsize + rsize + 1, lok && rok
+	}
+	_, _, size, ok := check(t.root)
+	if size != t.size {
+		fmt.Printf("size was %d but tree had %d nodes\n", t.size, size)
+		return false
+	}
+	return ok
+}
+
+func collect(it *iterator.Iterator) *list.ConcurrentList {
+	return it.PullAll().TakeWhile(func(x interface{}) bool { return x != iterator.EndOfIteration })
+}
+
+func random(n uint) []Interval {
+	ivs := make([]Interval, n)
+	for i := range ivs {
+		low := rand.Float64() * 1000
+		ivs[i] = Interval{Low: low, High: low + rand.Float64()*50, Val: i}
+	}
+	return ivs
+}
+
+func TestTree_Insert(t *testing.T) {
+	should := fTree("Insert", t)
+	should("keep the tree balanced and augmented", true, func() interface{} {
+		return isValid(New(random(MANY)...))
+	})
+	should("order intervals by low bound", "{[1, 2] [1, 5] [3, 4]}", func() interface{} {
+		return New(Interval{Low: 3, High: 4}, Interval{Low: 1, High: 5}, Interval{Low: 1, High: 2}).String()
+	})
+}
+
+func TestTree_Delete(t *testing.T) {
+	should := fTree("Delete", t)
+	should("remove intervals and stay valid", true, func() interface{} {
+		ivs := random(MANY)
+		tree := New(ivs...)
+		for _, i := range rand.Perm(len(ivs))[:MANY/2] {
+			if !tree.Delete(ivs[i]) {
+				return false
+			}
+		}
+		return tree.Size() == MANY/2 && isValid(tree)
+	})
+	should("report missing intervals", false, func() interface{} {
+		return New(Interval{Low: 1, High: 2, Val: "a"}).Delete(Interval{Low: 1, High: 2, Val: "b"})
+	})
+	should("compare uncomparable payloads by value", []interface{}{true, uint(0)}, func() interface{} {
+		tree := New(Interval{Low: 1, High: 2, Val: []int{1, 2}})
+		return []interface{}{tree.Delete(Interval{Low: 1, High: 2, Val: []int{1, 2}}), tree.Size()}
+	})
+	should("compare Equatable payloads with Eq", true, func() interface{} {
+		return New(Interval{Low: 1, High: 2, Val: list.New(1)}).Delete(Interval{Low: 1, High: 2, Val: list.New(1)})
+	})
+	should("remove only one of several duplicates", uint(1), func() interface{} {
+		tree := New(Interval{Low: 1, High: 2}, Interval{Low: 1, High: 2})
+		tree.Delete(Interval{Low: 1, High: 2})
+		return tree.Size()
+	})
+}
+
+func TestTree_Overlapping(t *testing.T) {
+	should := fTree("Overlapping", t)
+	tree := New(Interval{Low: 0, High: 3}, Interval{Low: 5, High: 8}, Interval{Low: 6, High: 10}, Interval{Low: 15, High: 23}, Interval{Low: 17, High: 19})
+	should("find intervals intersecting the query", list.New(Interval{Low: 5, High: 8}, Interval{Low: 6, High: 10}), func() interface{} {
+		return collect(tree.Overlapping(4, 6))
+	})
+	should("include touching bounds", list.New(Interval{Low: 0, High: 3}), func() interface{} {
+		return collect(tree.Overlapping(-1, 0))
+	})
+	should("find nothing in gaps", list.New(), func() interface{} {
+		return collect(tree.Overlapping(11, 14))
+	})
+	should("agree with a linear scan", true, func() interface{} {
+		ivs := random(MANY)
+		tree := New(ivs...)
+		for i := 0; i < 100; i++ {
+			low := rand.Float64() * 1000
+			high := low + rand.Float64()*20
+			expected := 0
+			for _, iv := range ivs {
+				if iv.Overlaps(low, high) {
+					expected++
+				}
+			}
+			if collect(tree.Overlapping(low, high)).Size() != uint(expected) {
+				return false
+			}
+		}
+		return true
+	})
+	should("see changes made ahead of the position reached", list.New(Interval{Low: 0, High: 3}, Interval{Low: 1, High: 2}, Interval{Low: 5, High: 8}), func() interface{} {
+		tree := New(Interval{Low: 0, High: 3}, Interval{Low: 5, High: 8}, Interval{Low: 6, High: 10})
+		it := tree.Overlapping(0, 9)
+		first := it.Pull()
+		tree.Insert(Interval{Low: -1, High: 1})
+		tree.Insert(Interval{Low: 1, High: 2})
+		tree.Delete(Interval{Low: 6, High: 10})
+		rest := collect(it)
+		rest.PushFront(first)
+		return rest
+	})
+}
+
+func TestTree_Stab(t *testing.T) {
+	should := fTree("Stab", t)
+	should("find intervals containing the point", list.New(Interval{Low: 15, High: 23}, Interval{Low: 17, High: 19}), func() interface{} {
+		return collect(New(Interval{Low: 0, High: 3}, Interval{Low: 15, High: 23}, Interval{Low: 17, High: 19}).Stab(18))
+	})
+}
+
+func TestTree_Merge(t *testing.T) {
+	should := fTree("Merge", t)
+	should("coalesce overlapping intervals", list.New(Interval{Low: 0, High: 3}, Interval{Low: 5, High: 10}, Interval{Low: 15, High: 23}), func() interface{} {
+		return New(Interval{Low: 0, High: 3}, Interval{Low: 5, High: 8}, Interval{Low: 6, High: 10}, Interval{Low: 15, High: 23}, Interval{Low: 17, High: 19}).Merge()
+	})
+	should("merge nothing when empty", list.New(), func() interface{} { return New().Merge() })
+}
+
+func TestTree_IsThreadSafe(t *testing.T) {
+	should := fTree("general concurrency", t)
+	should("not loose intervals", true, func() interface{} {
+		tree := New()
+		wg := sync.WaitGroup{}
+		for _, iv := range random(MANY) {
+			wg.Add(1)
+			go func(iv Interval) {
+				defer wg.Done()
+				tree.Insert(iv)
+				tree.Stab(iv.Low)
+			}(iv)
+		}
+		wg.Wait()
+		return tree.Size() == MANY && isValid(tree)
+	})
+}
@@ -0,0 +1,23 @@
+package iterator
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nl253/DataStructures/csvio"
+)
+
+// FromCSV reads the records of r as they are pulled, see csvio.Decoder. A
+// malformed record panics.
+func FromCSV(r io.Reader, opts csvio.Options) *Iterator {
+	d := csvio.NewDecoder(r, opts)
+	return FromClojure(func() interface{} {
+		if x, ok := d.Next(); ok {
+			return x
+		}
+		if err := d.Err(); err != nil {
+			panic(fmt.Sprintf("[ERROR] failed to read CSV - %s", err.Error()))
+		}
+		return EndOfIteration
+	})
+}
@@ -0,0 +1,21 @@
+package iterator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nl253/DataStructures/csvio"
+)
+
+func TestIterator_FromCSV(t *testing.T) {
+	should := fIter("FromCSV", t)
+	should("read records lazily", []interface{}{[]string{"a", "b"}, []string{"c", "d"}}, func() interface{} {
+		it := FromCSV(strings.NewReader("a,b\nc,d\n"), csvio.Options{})
+		return []interface{}{it.Pull(), it.Pull()}
+	})
+	should("panic on malformed records", true, func() (panicked interface{}) {
+		defer func() { panicked = recover() != nil }()
+		FromCSV(strings.NewReader("a,b\nc\n"), csvio.Options{}).Consume()
+		return false
+	})
+}
@@ -8,6 +8,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/nl253/DataStructures"
+	"github.com/nl253/DataStructures/cache"
+	"github.com/nl253/DataStructures/hyperloglog"
 	"github.com/nl253/DataStructures/list"
 )
 
@@ -86,6 +89,18 @@ func FromFileLines(filePath string) *Iterator {
 	return FromFileSplit(filePath, '\n')
 }
 
+func FromSlice(xs []interface{}) *Iterator {
+	i := 0
+	return New(nil, func(_ interface{}) interface{} {
+		if i < len(xs) {
+			tmp := xs[i]
+			i++
+			return tmp
+		}
+		return EndOfIteration
+	})
+}
+
 func FromStr(s string) *Iterator {
 	i := 0
 	return New(nil, func(_ interface{}) interface{} {
@@ -260,15 +275,76 @@ func (iter *Iterator) ForEach(f func(interface{})) *Iterator {
 func (iter *Iterator) Filter(f func(interface{}) bool) *Iterator {
 	iter.lk.Lock()
 	defer iter.lk.Unlock()
-	return New(iter.state, func(state interface{}) interface{} {
-		focus := state
-		for !f(focus) && focus != EndOfIteration {
-			focus = iter.Pull()
+	return New(iter.state, func(_ interface{}) interface{} {
+		for x := iter.Pull(); ; x = iter.Pull() {
+			if x == EndOfIteration || f(x) {
+				return x
+			}
+		}
+	})
+}
+
+func (iter *Iterator) Distinct() *Iterator {
+	return iter.DistinctBy(identity)
+}
+
+// DistinctBy drops elements whose key has been seen before. Keys must be
+// comparable and are all retained, see DistinctByN for bounded memory.
+func (iter *Iterator) DistinctBy(keyFn func(interface{}) interface{}) *Iterator {
+	seen := make(map[interface{}]bool)
+	return iter.Filter(func(x interface{}) bool {
+		k := keyFn(x)
+		if seen[k] {
+			return false
 		}
-		return focus
+		seen[k] = true
+		return true
 	})
 }
 
+func (iter *Iterator) DistinctN(n uint) *Iterator {
+	return iter.DistinctByN(identity, n)
+}
+
+// DistinctByN remembers only the n most recently seen keys, so a duplicate
+// further back than that is let through again. n must be positive, use
+// DistinctBy for an unbounded set of keys.
+func (iter *Iterator) DistinctByN(keyFn func(interface{}) interface{}, n uint) *Iterator {
+	if n == 0 {
+		panic("[ERROR] DistinctByN needs to remember at least 1 key")
+	}
+	seen := cache.NewLRU(n)
+	return iter.Filter(func(x interface{}) bool {
+		k := keyFn(x)
+		if _, ok := seen.Get(k); ok {
+			return false
+		}
+		seen.Set(k, true)
+		return true
+	})
+}
+
+// DistinctUntilChanged drops elements equal to the one before them, see
+// DataStructures.Eq.
+func (iter *Iterator) DistinctUntilChanged() *Iterator {
+	return iter.DistinctUntilChangedBy(DataStructures.Eq)
+}
+
+func (iter *Iterator) DistinctUntilChangedBy(eq func(interface{}, interface{}) bool) *Iterator {
+	var prev interface{}
+	first := true
+	return iter.Filter(func(x interface{}) bool {
+		if !first && eq(x, prev) {
+			return false
+		}
+		first = false
+		prev = x
+		return true
+	})
+}
+
+func identity(x interface{}) interface{} { return x }
+
 func (iter *Iterator) ReduceN(init interface{}, f func(interface{}, interface{}) interface{}, n uint) interface{} {
 	iter.lk.Lock()
 	defer iter.lk.Unlock()
@@ -301,6 +377,10 @@ func (iter *Iterator) Count() int {
 	return iter.Map(func(x interface{}) interface{} { return 1 }).Sum()
 }
 
+func (iter *Iterator) CountDistinctApprox(precision uint8) uint64 {
+	return iter.ReduceAll(hyperloglog.New(precision), hyperloglog.Reducer).(*hyperloglog.HyperLogLog).Count()
+}
+
 func (iter *Iterator) Clone() *Iterator {
 	iter.lk.Lock()
 	defer iter.lk.Unlock()
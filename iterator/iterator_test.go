@@ -123,3 +123,60 @@ func TestIterator_FromStr(t *testing.T) {
 		return FromStr("").Take(0).PullN(0)
 	})
 }
+
+func TestIterator_CountDistinctApprox(t *testing.T) {
+	should := fIter("CountDistinctApprox", t)
+	should("count distinct elems", uint64(5), func() interface{} {
+		return Ints().Map(func(x interface{}) interface{} { return x.(int) % 5 }).Take(100).CountDistinctApprox(12)
+	})
+}
+
+func TestIterator_FromSlice(t *testing.T) {
+	should := fIter("FromSlice", t)
+	should("iter over slice elems", []interface{}{1, "a", 2.0, EndOfIteration}, func() interface{} {
+		return FromSlice([]interface{}{1, "a", 2.0}).PullN(4)
+	})
+	should("iter over empty slice", EndOfIteration, func() interface{} {
+		return FromSlice([]interface{}{}).Pull()
+	})
+}
+
+func TestIterator_Filter(t *testing.T) {
+	should := fIter("Filter", t)
+	should("keep matching elements", []interface{}{0, 2, 4}, func() interface{} {
+		return Ints().Filter(func(x interface{}) bool { return x.(int)%2 == 0 }).PullN(3)
+	})
+	should("end with the source", []interface{}{1, EndOfIteration}, func() interface{} {
+		return Ints().Take(3).Filter(func(x interface{}) bool { return x.(int) == 1 }).PullN(2)
+	})
+}
+
+func TestIterator_Distinct(t *testing.T) {
+	should := fIter("Distinct", t)
+	should("drop repeated elements", []interface{}{0, 1, 2, EndOfIteration}, func() interface{} {
+		return Ints().Map(func(x interface{}) interface{} { return x.(int) % 3 }).Take(10).Distinct().PullN(4)
+	})
+	should("drop elements with repeated keys", []interface{}{0, 1}, func() interface{} {
+		return Ints().DistinctBy(func(x interface{}) interface{} { return x.(int) % 2 }).PullN(2)
+	})
+	should("forget keys beyond the bound", []interface{}{1, 2, 3, 1}, func() interface{} {
+		return FromSlice([]interface{}{1, 2, 2, 3, 1}).DistinctN(2).PullN(4)
+	})
+	should("refuse a bound of 0", true, func() (panicked interface{}) {
+		defer func() { panicked = recover() != nil }()
+		FromSlice([]interface{}{1, 1}).DistinctN(0)
+		return false
+	})
+}
+
+func TestIterator_DistinctUntilChanged(t *testing.T) {
+	should := fIter("DistinctUntilChanged", t)
+	should("drop consecutive duplicates", []interface{}{1, 2, 1, EndOfIteration}, func() interface{} {
+		return FromSlice([]interface{}{1, 1, 2, 2, 1}).DistinctUntilChanged().PullN(4)
+	})
+	should("use a custom equality", []interface{}{1, 5}, func() interface{} {
+		return FromSlice([]interface{}{1, 2, 5, 6}).DistinctUntilChangedBy(func(x interface{}, y interface{}) bool {
+			return x.(int)-y.(int) < 2
+		}).PullN(2)
+	})
+}
@@ -0,0 +1,24 @@
+package iterator
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/nl253/DataStructures/ndjson"
+)
+
+// FromJSONLines decodes one value of typ per line of r as it is pulled, see
+// ndjson.Decoder. With ndjson.FailFast a malformed line panics.
+func FromJSONLines(r io.Reader, typ reflect.Type, policy ndjson.Policy) *Iterator {
+	d := ndjson.NewDecoder(r, typ, policy)
+	return FromClojure(func() interface{} {
+		if x, ok := d.Next(); ok {
+			return x
+		}
+		if err := d.Err(); err != nil {
+			panic(fmt.Sprintf("[ERROR] failed to decode JSON lines - %s", err.Error()))
+		}
+		return EndOfIteration
+	})
+}
@@ -0,0 +1,26 @@
+package iterator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nl253/DataStructures/list"
+	"github.com/nl253/DataStructures/ndjson"
+)
+
+type event struct {
+	ID int `json:"id"`
+}
+
+func TestIterator_FromJSONLines(t *testing.T) {
+	should := fIter("FromJSONLines", t)
+	should("decode lines lazily", list.New(event{1}, event{2}, EndOfIteration), func() interface{} {
+		return FromJSONLines(strings.NewReader("{\"id\": 1}\nnope\n{\"id\": 2}"), reflect.TypeOf(event{}), ndjson.Skip).PullAll()
+	})
+	should("panic on malformed lines with FailFast", true, func() (panicked interface{}) {
+		defer func() { panicked = recover() != nil }()
+		FromJSONLines(strings.NewReader("{\"id\": 1}\nnope"), reflect.TypeOf(event{}), ndjson.FailFast).Consume()
+		return false
+	})
+}
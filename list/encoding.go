@@ -0,0 +1,64 @@
+package list
+
+import (
+	"encoding/json"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func init() {
+	codec.Register("list.ConcurrentList", &ConcurrentList{})
+}
+
+type snapshot struct {
+	Elems []interface{}
+}
+
+// MarshalBinary gob-encodes the elements, their types must be registered
+// with codec.Register. It also makes ConcurrentList usable with gob.
+func (xs *ConcurrentList) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary(snapshot{Elems: xs.ToSlice()})
+}
+
+func (xs *ConcurrentList) UnmarshalBinary(data []byte) error {
+	var s snapshot
+	if err := codec.UnmarshalBinary(data, &s); err != nil {
+		return err
+	}
+	xs.replace(s.Elems)
+	return nil
+}
+
+// MarshalJSON writes an array of codec.Value so that elements keep their
+// types when read back.
+func (xs *ConcurrentList) MarshalJSON() ([]byte, error) {
+	vs, err := codec.WrapAll(xs.ToSlice())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(vs)
+}
+
+func (xs *ConcurrentList) UnmarshalJSON(data []byte) error {
+	var vs []codec.Value
+	if err := json.Unmarshal(data, &vs); err != nil {
+		return err
+	}
+	elems, err := codec.UnwrapAll(vs)
+	if err != nil {
+		return err
+	}
+	xs.replace(elems)
+	return nil
+}
+
+// replace swaps the contents of xs for elems, xs may be the zero value.
+func (xs *ConcurrentList) replace(elems []interface{}) {
+	newXS := New(elems...)
+	if xs.lk == nil {
+		xs.lk = newXS.lk
+	}
+	xs.lk.Lock()
+	xs.fst, xs.lst, xs.size = newXS.fst, newXS.lst, newXS.size
+	xs.lk.Unlock()
+}
@@ -0,0 +1,56 @@
+package list
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestConcurrentList_MarshalBinary(t *testing.T) {
+	should := fCon("MarshalBinary", t)
+	should("round trip elements of mixed types", New(1, "a", 2.5, nil, uint8(3)), func() interface{} {
+		data, err := New(1, "a", 2.5, nil, uint8(3)).MarshalBinary()
+		if err != nil {
+			return err
+		}
+		xs := New(9)
+		if err := xs.UnmarshalBinary(data); err != nil {
+			return err
+		}
+		return xs
+	})
+	should("round trip nested lists through gob", "[[1 2] [] x]", func() interface{} {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(New(New(1, 2), New(), "x")); err != nil {
+			return err
+		}
+		var xs ConcurrentList
+		if err := gob.NewDecoder(&buf).Decode(&xs); err != nil {
+			return err
+		}
+		return xs.String()
+	})
+}
+
+func TestConcurrentList_MarshalJSON(t *testing.T) {
+	should := fCon("MarshalJSON", t)
+	should("round trip keeping element types", New(1, int64(2), "a", true, New(uint(3))).String(), func() interface{} {
+		data, err := json.Marshal(New(1, int64(2), "a", true, New(uint(3))))
+		if err != nil {
+			return err
+		}
+		xs := New()
+		if err := json.Unmarshal(data, xs); err != nil {
+			return err
+		}
+		if _, ok := xs.Nth(1).(int64); !ok {
+			return xs.Nth(1)
+		}
+		return xs.String()
+	})
+	should("refuse unregistered elements", true, func() interface{} {
+		_, err := json.Marshal(New(struct{}{}))
+		return err != nil
+	})
+}
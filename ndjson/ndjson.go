@@ -0,0 +1,111 @@
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Policy decides what a Decoder does with lines that fail to decode.
+type Policy int
+
+const (
+	// FailFast stops decoding at the first malformed line.
+	FailFast Policy = iota
+	// Skip drops malformed lines.
+	Skip
+	// Emit yields a *LineError in place of each malformed line.
+	Emit
+)
+
+// MaxLineSize is the longest line a Decoder accepts.
+const MaxLineSize = 16 << 20
+
+type LineError struct {
+	Line uint
+	Text string
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("ndjson: line %d: %s", e.Line, e.Err.Error())
+}
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+// Decoder reads one JSON value per line, blank lines are ignored.
+type Decoder struct {
+	scanner *bufio.Scanner
+	typ     reflect.Type
+	policy  Policy
+	line    uint
+	err     error
+}
+
+// NewDecoder decodes lines into new values of typ, or into the generic
+// interface{} representation when typ is nil.
+func NewDecoder(r io.Reader, typ reflect.Type, policy Policy) *Decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), MaxLineSize)
+	return &Decoder{scanner: scanner, typ: typ, policy: policy}
+}
+
+// Next returns the next value, or false once the input is exhausted or
+// decoding failed, in which case Err says why.
+func (d *Decoder) Next() (interface{}, bool) {
+	for d.err == nil && d.scanner.Scan() {
+		d.line++
+		text := bytes.TrimSpace(d.scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		x, err := d.decode(text)
+		if err == nil {
+			return x, true
+		}
+		lineErr := &LineError{Line: d.line, Text: string(text), Err: err}
+		switch d.policy {
+		case Skip:
+			continue
+		case Emit:
+			return lineErr, true
+		default:
+			d.err = lineErr
+			return nil, false
+		}
+	}
+	if d.err == nil {
+		d.err = d.scanner.Err()
+	}
+	return nil, false
+}
+
+func (d *Decoder) Err() error { return d.err }
+
+func (d *Decoder) decode(text []byte) (interface{}, error) {
+	if d.typ == nil {
+		var x interface{}
+		err := json.Unmarshal(text, &x)
+		return x, err
+	}
+	ptr := reflect.New(d.typ)
+	if err := json.Unmarshal(text, ptr.Interface()); err != nil {
+		return nil, err
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// Encode writes x as a single line of JSON. HTML characters are left as
+// they are.
+func Encode(w io.Writer, x interface{}) (int, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(x); err != nil {
+		return 0, err
+	}
+	return w.Write(buf.Bytes())
+}
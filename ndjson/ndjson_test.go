@@ -0,0 +1,71 @@
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ut "github.com/nl253/Testing"
+)
+
+var fDecoder = ut.Test("Decoder")
+
+type event struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+const input = "{\"id\": 1, \"name\": \"a\"}\n\n{oops\n{\"id\": 2, \"name\": \"b\"}\n"
+
+func decodeAll(d *Decoder) []interface{} {
+	xs := make([]interface{}, 0)
+	for x, ok := d.Next(); ok; x, ok = d.Next() {
+		xs = append(xs, x)
+	}
+	return xs
+}
+
+func TestDecoder_Next(t *testing.T) {
+	should := fDecoder("Next", t)
+	should("decode into typ", []interface{}{event{1, "a"}, event{2, "b"}}, func() interface{} {
+		return decodeAll(NewDecoder(strings.NewReader(input), reflect.TypeOf(event{}), Skip))
+	})
+	should("decode generic values without typ", []interface{}{map[string]interface{}{"id": 1.0}, 2.0}, func() interface{} {
+		return decodeAll(NewDecoder(strings.NewReader("{\"id\": 1}\r\n2"), nil, FailFast))
+	})
+	should("stop at the first malformed line", []interface{}{1, uint(3), "{oops"}, func() interface{} {
+		d := NewDecoder(strings.NewReader(input), reflect.TypeOf(event{}), FailFast)
+		n := len(decodeAll(d))
+		var lineErr *LineError
+		if !errors.As(d.Err(), &lineErr) {
+			return d.Err()
+		}
+		return []interface{}{n, lineErr.Line, lineErr.Text}
+	})
+	should("emit malformed lines as errors", uint(3), func() interface{} {
+		xs := decodeAll(NewDecoder(strings.NewReader(input), reflect.TypeOf(event{}), Emit))
+		return xs[1].(*LineError).Line
+	})
+	should("report lines longer than MaxLineSize", bufio.ErrTooLong, func() interface{} {
+		d := NewDecoder(strings.NewReader("\""+strings.Repeat("x", MaxLineSize)+"\""), nil, Skip)
+		decodeAll(d)
+		return d.Err()
+	})
+}
+
+func TestEncode(t *testing.T) {
+	should := ut.Test("Encode")("Encode", t)
+	should("write one line without escaping HTML", "{\"id\":1,\"name\":\"<a>\"}\n", func() interface{} {
+		var buf bytes.Buffer
+		_, _ = Encode(&buf, event{1, "<a>"})
+		return buf.String()
+	})
+	should("round trip through a Decoder", []interface{}{event{1, "a"}}, func() interface{} {
+		var buf bytes.Buffer
+		_, _ = Encode(&buf, event{1, "a"})
+		return decodeAll(NewDecoder(&buf, reflect.TypeOf(event{}), FailFast))
+	})
+}
@@ -0,0 +1,69 @@
+package stream
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nl253/DataStructures/checkpoint"
+)
+
+// ResumeFileLines is FromFileLines emitting *checkpoint.Record values whose
+// Pos is the byte offset after each line. Reading starts at the offset saved
+// by cp, so acknowledging records with cp.Ack once they have been handled
+// gives at-least-once processing across restarts.
+func ResumeFileLines(filePath string, cp *checkpoint.Checkpointer) *Stream {
+	offset, err := cp.Start()
+	if err != nil {
+		return New().fail(err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return New().fail(err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		_ = file.Close()
+		return New().fail(err)
+	}
+	return fromReader(file, file, func(r io.Reader, s *Stream) error {
+		br := bufio.NewReader(r)
+		pos := offset
+		for !s.isClosed() {
+			line, err := br.ReadString('\n')
+			if len(line) > 0 {
+				pos += int64(len(line))
+				s.PushBack(cp.Track(pos, strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")))
+			}
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Resume skips as many elements of s as cp has saved and wraps the rest in
+// *checkpoint.Record values whose Pos is the number of elements read. It
+// only makes sense for sources that produce the same elements every run.
+func (s *Stream) Resume(cp *checkpoint.Checkpointer) *Stream {
+	skip, err := cp.Start()
+	if err != nil {
+		return s.derive().fail(err)
+	}
+	newS := s.derive()
+	go func() {
+		n := int64(0)
+		s.forEach(func(x interface{}) {
+			n++
+			if n > skip {
+				newS.PushBack(cp.Track(n, x))
+			}
+		})
+		newS.fail(s.Err())
+	}()
+	return newS
+}
@@ -0,0 +1,48 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/nl253/DataStructures/checkpoint"
+	"github.com/nl253/DataStructures/list"
+)
+
+func vals(s *Stream) *list.ConcurrentList {
+	return s.Map(func(x interface{}) interface{} { return x.(*checkpoint.Record).Val }).PullAll()
+}
+
+func TestStream_ResumeFileLines(t *testing.T) {
+	should := fStream("ResumeFileLines", t)
+	should("resume after the last acknowledged line", []interface{}{list.New("one", "two"), list.New("three", "four")}, func() interface{} {
+		path := tempFile(t, "one\r\ntwo\nthree\nfour")
+		store, _ := checkpoint.NewFileStore(t.TempDir())
+		cp := checkpoint.New(store, "lines", 1)
+		first := vals(ResumeFileLines(path, cp).Take(2).ForEach(cp.Ack))
+		return []interface{}{first, vals(ResumeFileLines(path, checkpoint.New(store, "lines", 1)))}
+	})
+	should("redeliver unacknowledged lines", list.New("b", "c"), func() interface{} {
+		path := tempFile(t, "a\nb\nc\n")
+		store := checkpoint.NewMemStore()
+		cp := checkpoint.New(store, "lines", 1)
+		s := ResumeFileLines(path, cp)
+		cp.Ack(s.Pull())
+		s.Pull()
+		return vals(ResumeFileLines(path, checkpoint.New(store, "lines", 1)))
+	})
+	should("end with Err set for missing files", true, func() interface{} {
+		s := ResumeFileLines("missing.txt", checkpoint.New(checkpoint.NewMemStore(), "lines", 1))
+		s.Consume()
+		return s.Err() != nil
+	})
+}
+
+func TestStream_Resume(t *testing.T) {
+	should := fStream("Resume", t)
+	should("skip processed elements", []interface{}{list.New(0, 1, 2), list.New(3, 4)}, func() interface{} {
+		store := checkpoint.NewMemStore()
+		cp := checkpoint.New(store, "ints", 0)
+		first := vals(Ints(0, 5).Resume(cp).Take(3).ForEach(cp.Ack))
+		_ = cp.Flush()
+		return []interface{}{first, vals(Ints(0, 5).Resume(checkpoint.New(store, "ints", 0)))}
+	})
+}
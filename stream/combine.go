@@ -0,0 +1,125 @@
+package stream
+
+import "sync"
+
+// Zip pairs up the n-th elements of every input as a []interface{}. It ends
+// with the shortest input, elements already taken from the others in that
+// round are pushed back so those streams can still be consumed.
+func Zip(ss ...*Stream) *Stream {
+	return ZipWith(func(xs ...interface{}) interface{} { return xs }, ss...)
+}
+
+func ZipWith(f func(xs ...interface{}) interface{}, ss ...*Stream) *Stream {
+	newS := New()
+	go func() {
+		if len(ss) == 0 {
+			newS.Close()
+			return
+		}
+		for {
+			xs := make([]interface{}, len(ss))
+			for i, s := range ss {
+				if xs[i] = s.Pull(); xs[i] == EndMarker {
+					for j := 0; j < i; j++ {
+						ss[j].PushFront(xs[j])
+					}
+					newS.fail(s.Err())
+					return
+				}
+			}
+			newS.PushBack(f(xs...))
+		}
+	}()
+	return newS
+}
+
+// Merge interleaves the inputs in whatever order their elements arrive and
+// ends once all of them have ended, with the error of the first input, in
+// argument order, that failed.
+func Merge(ss ...*Stream) *Stream {
+	newS := New()
+	wg := &sync.WaitGroup{}
+	wg.Add(len(ss))
+	for _, s := range ss {
+		go func(s *Stream) {
+			s.forEach(func(x interface{}) { newS.PushBack(x) })
+			wg.Done()
+		}(s)
+	}
+	go func() {
+		wg.Wait()
+		for _, s := range ss {
+			if err := s.Err(); err != nil {
+				newS.fail(err)
+				return
+			}
+		}
+		newS.Close()
+	}()
+	return newS
+}
+
+// Concat drains the inputs one after another, stopping at the first one
+// that fails.
+func Concat(ss ...*Stream) *Stream {
+	newS := New()
+	go func() {
+		for _, s := range ss {
+			s.forEach(func(x interface{}) { newS.PushBack(x) })
+			if err := s.Err(); err != nil {
+				newS.fail(err)
+				return
+			}
+		}
+		newS.Close()
+	}()
+	return newS
+}
+
+// CombineLatest emits a []interface{} of the latest element of every input
+// each time any of them produces one, once all have produced at least one.
+// It ends when all inputs have ended or as soon as one ends without ever
+// producing anything, since no combination can be emitted after that. An
+// input failing ends it straight away with that error.
+func CombineLatest(ss ...*Stream) *Stream {
+	newS := New()
+	if len(ss) == 0 {
+		return newS.Close()
+	}
+	lk := &sync.Mutex{}
+	latest := make([]interface{}, len(ss))
+	seen := make([]bool, len(ss))
+	nSeen := 0
+	running := len(ss)
+	done := false
+	for i, s := range ss {
+		go func(i int, s *Stream) {
+			for x := s.Pull(); x != EndMarker; x = s.Pull() {
+				lk.Lock()
+				if done {
+					lk.Unlock()
+					return
+				}
+				if !seen[i] {
+					seen[i] = true
+					nSeen++
+				}
+				latest[i] = x
+				if nSeen == len(ss) {
+					snapshot := make([]interface{}, len(ss))
+					copy(snapshot, latest)
+					newS.PushBack(snapshot)
+				}
+				lk.Unlock()
+			}
+			lk.Lock()
+			defer lk.Unlock()
+			running--
+			if err := s.Err(); !done && (running == 0 || !seen[i] || err != nil) {
+				done = true
+				newS.fail(err)
+			}
+		}(i, s)
+	}
+	return newS
+}
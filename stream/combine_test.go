@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+func TestStream_Zip(t *testing.T) {
+	should := fStream("Zip", t)
+	should("pair elements in lockstep", list.New([]interface{}{0, "a"}, []interface{}{1, "b"}).String(), func() interface{} {
+		return Zip(Range(0, 3, 1), New("a", "b").Close()).PullAll().String()
+	})
+	should("give back elements pulled from longer inputs", list.New(2), func() interface{} {
+		longer := Range(0, 3, 1)
+		Zip(longer, New("a", "b").Close()).Consume()
+		return longer.PullAll()
+	})
+	should("zip no streams to an empty stream", list.New(), func() interface{} { return Zip().PullAll() })
+	should("generate valid stream", true, func() interface{} { return isValid(Zip(Range(0, 3, 1), Range(0, 3, 1))) })
+}
+
+func TestStream_ZipWith(t *testing.T) {
+	should := fStream("ZipWith", t)
+	should("combine elements in lockstep", list.New(0, 2, 4), func() interface{} {
+		return ZipWith(func(xs ...interface{}) interface{} { return xs[0].(int) + xs[1].(int) }, Range(0, 3, 1), Range(0, 10, 1)).PullAll()
+	})
+}
+
+func TestStream_Merge(t *testing.T) {
+	should := fStream("Merge", t)
+	should("contain all elements of all inputs", uint(30), func() interface{} {
+		return Merge(Range(0, 10, 1), Range(10, 20, 1), Range(20, 30, 1)).Count()
+	})
+	should("keep the order within each input", true, func() interface{} {
+		prev := -1
+		return Merge(Range(0, 100, 1), New().Close()).Reduce(true, func(acc interface{}, x interface{}) interface{} {
+			ok := x.(int) > prev
+			prev = x.(int)
+			return acc.(bool) && ok
+		})
+	})
+	should("end when all inputs end", list.New(), func() interface{} { return Merge(New().Close(), New().Close()).PullAll() })
+	should("carry the error of a failed input", errFlaky, func() interface{} {
+		out := Merge(New(1).Close(), failing(2))
+		out.Consume()
+		return out.Err()
+	})
+	should("generate valid stream", true, func() interface{} { return isValid(Merge(Range(0, 3, 1), Range(0, 3, 1))) })
+}
+
+func TestStream_ConcatStreams(t *testing.T) {
+	should := fStream("Concat", t)
+	should("drain inputs in sequence", list.New(0, 1, "a", "b"), func() interface{} {
+		return Concat(Range(0, 2, 1), New().Close(), New("a", "b").Close()).PullAll()
+	})
+	should("stop at a failing input", []interface{}{list.New(1), errFlaky}, func() interface{} {
+		out := Concat(failing(1), New(2).Close())
+		return []interface{}{out.PullAll(), out.Err()}
+	})
+	should("generate valid stream", true, func() interface{} { return isValid(Concat(Range(0, 3, 1))) })
+}
+
+func TestStream_CombineLatest(t *testing.T) {
+	should := fStream("CombineLatest", t)
+	should("emit the latest of each input", []interface{}{[]interface{}{1, "x"}, []interface{}{2, "x"}, []interface{}{2, "y"}, EndMarker}, func() interface{} {
+		a := New(1)
+		b := New()
+		s := CombineLatest(a, b)
+		b.PushBack("x")
+		fst := s.Pull()
+		a.PushBack(2)
+		snd := s.Pull()
+		a.Close()
+		b.PushBack("y")
+		b.Close()
+		return []interface{}{fst, snd, s.Pull(), s.Pull()}
+	})
+	should("end when an input ends without emitting", list.New(), func() interface{} {
+		return CombineLatest(New(), New().Close()).PullAll()
+	})
+}
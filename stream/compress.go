@@ -0,0 +1,154 @@
+package stream
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrReadOnlyCompression = errors.New("stream: compression format can only be read")
+
+type Compression int
+
+const (
+	// AutoCompression picks a format from the file extension.
+	AutoCompression Compression = iota
+	NoCompression
+	Gzip
+	Zlib
+	// Bzip2 can only be read.
+	Bzip2
+)
+
+// CompressionOf maps .gz, .zz and .bz2 (and their long forms) to formats.
+func CompressionOf(filePath string) Compression {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".gz", ".gzip":
+		return Gzip
+	case ".zz", ".zlib":
+		return Zlib
+	case ".bz2", ".bzip2":
+		return Bzip2
+	default:
+		return NoCompression
+	}
+}
+
+func (c Compression) String() string {
+	switch c {
+	case AutoCompression:
+		return "auto"
+	case Gzip:
+		return "gzip"
+	case Zlib:
+		return "zlib"
+	case Bzip2:
+		return "bzip2"
+	default:
+		return "none"
+	}
+}
+
+func (c Compression) resolve(filePath string) Compression {
+	if c == AutoCompression {
+		return CompressionOf(filePath)
+	}
+	return c
+}
+
+// closers closes in order and reports the first failure.
+type closers []io.Closer
+
+func (cs closers) Close() error {
+	var first error
+	for _, c := range cs {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// openFile opens filePath for reading through a decompressor.
+func openFile(filePath string, c Compression) (io.Reader, io.Closer, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch c.resolve(filePath) {
+	case Gzip:
+		r, err := gzip.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, nil, err
+		}
+		return r, closers{r, file}, nil
+	case Zlib:
+		r, err := zlib.NewReader(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, nil, err
+		}
+		return r, closers{r, file}, nil
+	case Bzip2:
+		return bzip2.NewReader(file), file, nil
+	default:
+		return file, file, nil
+	}
+}
+
+// createFile creates filePath for writing through a compressor. Closing the
+// writer flushes the compressor before closing the file.
+func createFile(filePath string, c Compression) (io.WriteCloser, error) {
+	c = c.resolve(filePath)
+	if c == Bzip2 {
+		return nil, ErrReadOnlyCompression
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		return nil, err
+	}
+	switch c {
+	case Gzip:
+		w := gzip.NewWriter(file)
+		return writeCloser{w, closers{w, file}}, nil
+	case Zlib:
+		w := zlib.NewWriter(file)
+		return writeCloser{w, closers{w, file}}, nil
+	default:
+		return file, nil
+	}
+}
+
+type writeCloser struct {
+	io.Writer
+	io.Closer
+}
+
+func FromFileCompressed(filePath string, c Compression) *Stream {
+	return fromFileCompressed(filePath, c, readBytes)
+}
+
+func FromFileLinesCompressed(filePath string, c Compression) *Stream {
+	return fromFileCompressed(filePath, c, readSplit(bufio.ScanLines))
+}
+
+// ToFileCompressed is ToFile with an explicit format, the count is of bytes
+// before compression.
+func (s *Stream) ToFileCompressed(filePath string, c Compression) (int64, error) {
+	w, err := createFile(filePath, c)
+	if err != nil {
+		return 0, err
+	}
+	written, err := s.ToWriter(w, Raw)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return written, err
+}
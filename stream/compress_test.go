@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+// bzipped is "a\nb\n" compressed with bzip2.
+var bzipped = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x3c, 0x85,
+	0x41, 0x12, 0x00, 0x00, 0x01, 0x41, 0x00, 0x00, 0x10, 0x30, 0x00, 0x20,
+	0x00, 0x30, 0xcc, 0x0c, 0x7a, 0x82, 0x71, 0x77, 0x24, 0x53, 0x85, 0x09,
+	0x03, 0xc8, 0x54, 0x11, 0x20,
+}
+
+func TestStream_CompressionOf(t *testing.T) {
+	should := fStream("CompressionOf", t)
+	should("pick formats by extension", []Compression{Gzip, Zlib, Bzip2, NoCompression}, func() interface{} {
+		return []Compression{CompressionOf("a.log.GZ"), CompressionOf("a.zz"), CompressionOf("a.bz2"), CompressionOf("a.txt")}
+	})
+}
+
+func TestStream_ToFileCompressed(t *testing.T) {
+	should := fStream("ToFileCompressed", t)
+	lines := strings.Repeat("some line\n", 1000)
+	for _, ext := range []string{".gz", ".zz", ".txt"} {
+		should("round trip "+ext+" files", list.New(uint(1000), true), func() interface{} {
+			path := filepath.Join(t.TempDir(), "out"+ext)
+			if _, err := FromStr(lines).ToFile(path); err != nil {
+				return err
+			}
+			raw, _ := ioutil.ReadFile(path)
+			compressed := ext == ".txt" || len(raw) < len(lines)
+			return list.New(FromFileLines(path).Count(), compressed)
+		})
+	}
+	should("write gzip when asked explicitly", "abc", func() interface{} {
+		path := filepath.Join(t.TempDir(), "out")
+		if _, err := FromStr("abc").ToFileCompressed(path, Gzip); err != nil {
+			return err
+		}
+		raw, _ := ioutil.ReadFile(path)
+		r, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return err
+		}
+		content, _ := ioutil.ReadAll(r)
+		return string(content)
+	})
+	should("refuse to write bzip2", ErrReadOnlyCompression, func() interface{} {
+		_, err := FromStr("abc").ToFile(filepath.Join(t.TempDir(), "out.bz2"))
+		return err
+	})
+}
+
+func TestStream_FromFileCompressed(t *testing.T) {
+	should := fStream("FromFileCompressed", t)
+	should("read bzip2 files", list.New("a", "b"), func() interface{} {
+		path := filepath.Join(t.TempDir(), "in.bz2")
+		_ = ioutil.WriteFile(path, bzipped, 0644)
+		return FromFileLines(path).PullAll()
+	})
+	should("decompress when asked explicitly", "a\nb\n", func() interface{} {
+		path := filepath.Join(t.TempDir(), "in")
+		_ = ioutil.WriteFile(path, bzipped, 0644)
+		return FromFileCompressed(path, Bzip2).Concat()
+	})
+	should("read lines of explicitly compressed files", list.New("x"), func() interface{} {
+		path := filepath.Join(t.TempDir(), "in")
+		_, _ = New("x\n").Close().ToFileCompressed(path, Zlib)
+		return FromFileLinesCompressed(path, Zlib).PullAll()
+	})
+	should("end with Err set for corrupt input", true, func() interface{} {
+		path := filepath.Join(t.TempDir(), "in.gz")
+		_ = ioutil.WriteFile(path, []byte("this is not gzip data"), 0644)
+		s := FromFileLines(path)
+		s.Consume()
+		return s.Err() == gzip.ErrHeader
+	})
+}
@@ -0,0 +1,36 @@
+package stream
+
+import (
+	"io"
+
+	"github.com/nl253/DataStructures/csvio"
+)
+
+// FromCSV emits the records of r, see csvio.Decoder. A malformed record
+// ends the stream and is reported by Err.
+func FromCSV(r io.Reader, opts csvio.Options) *Stream {
+	s := New()
+	go func() {
+		d := csvio.NewDecoder(r, opts)
+		for x, ok := d.Next(); ok; x, ok = d.Next() {
+			s.PushBack(x)
+		}
+		s.fail(d.Err())
+	}()
+	return s
+}
+
+// ToCSV writes columns as a header, unless there are none, followed by one
+// record per element, see csvio.Record.
+func (s *Stream) ToCSV(w io.Writer, columns []string) (int64, error) {
+	written := int64(0)
+	if len(columns) > 0 {
+		n, err := csvio.WriteRecord(w, columns)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	n, err := s.ToWriter(w, csvio.Encoder(columns))
+	return written + n, err
+}
@@ -0,0 +1,35 @@
+package stream
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nl253/DataStructures/csvio"
+)
+
+func TestStream_FromCSV(t *testing.T) {
+	should := fStream("FromCSV", t)
+	should("emit records keyed by header", []interface{}{map[string]string{"a": "1", "b": "2"}, EndMarker}, func() interface{} {
+		s := FromCSV(strings.NewReader("a,b\n1,2\n"), csvio.Options{Header: true})
+		return []interface{}{s.Pull(), s.Pull()}
+	})
+	should("end with Err on malformed records", true, func() interface{} {
+		s := FromCSV(strings.NewReader("a,\"b\n"), csvio.Options{})
+		s.Consume()
+		return s.Err() != nil
+	})
+}
+
+func TestStream_ToCSV(t *testing.T) {
+	should := fStream("ToCSV", t)
+	should("write a header and records", []interface{}{"a,b\n1,2\n", int64(8), nil}, func() interface{} {
+		var buf bytes.Buffer
+		n, err := FromCSV(strings.NewReader("a,b\n1,2\n"), csvio.Options{Header: true}).ToCSV(&buf, []string{"a", "b"})
+		return []interface{}{buf.String(), n, err}
+	})
+	should("fail on elements that are not records", csvio.ErrUnknownRecord, func() interface{} {
+		_, err := New(1).Close().ToCSV(&bytes.Buffer{}, nil)
+		return err
+	})
+}
@@ -0,0 +1,202 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// SymlinkPolicy decides what FromDir does with symbolic links.
+type SymlinkPolicy int
+
+const (
+	SkipSymlinks SymlinkPolicy = iota
+	// IncludeSymlinks emits links themselves without following them.
+	IncludeSymlinks
+	// FollowSymlinks treats links as their targets, directories reached
+	// twice are only walked once.
+	FollowSymlinks
+)
+
+type DirOptions struct {
+	// Glob is matched against base names, empty matches everything.
+	Glob string
+	// MaxDepth limits how deep FromDir descends, entries of root are at
+	// depth 1. Zero means no limit.
+	MaxDepth int
+	Symlinks SymlinkPolicy
+	// Dirs emits directories as well as files.
+	Dirs    bool
+	Context context.Context
+}
+
+// Entry is an element of FromDir. Err is set, and Info may be nil, when
+// Path could not be read.
+type Entry struct {
+	Path  string
+	Depth int
+	Info  os.FileInfo
+	Err   error
+}
+
+func (e *Entry) String() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s (%s)", e.Path, e.Err.Error())
+	}
+	return e.Path
+}
+
+// FromDir walks root in lexical order emitting *Entry values. Unreadable
+// entries are emitted with Err set and the walk carries on. The walk stops
+// when opts.Context is done, with Err set to its error, or when the stream
+// is closed.
+func FromDir(root string, opts DirOptions) *Stream {
+	s := New()
+	if _, err := filepath.Match(opts.Glob, ""); err != nil {
+		return s.fail(err)
+	}
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+	w := &dirWalker{s: s, opts: opts, seen: make(map[string]bool)}
+	go func() {
+		if opts.Symlinks == FollowSymlinks {
+			w.visit(root)
+		}
+		w.walk(root, 1)
+		s.fail(opts.Context.Err())
+	}()
+	return s
+}
+
+type dirWalker struct {
+	s    *Stream
+	opts DirOptions
+	seen map[string]bool
+}
+
+// visit reports whether dir is walked for the first time.
+func (w *dirWalker) visit(dir string) bool {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return true
+	}
+	if w.seen[real] {
+		return false
+	}
+	w.seen[real] = true
+	return true
+}
+
+func (w *dirWalker) stopped() bool {
+	return w.opts.Context.Err() != nil || w.s.isClosed()
+}
+
+func (w *dirWalker) emit(path string, depth int, info os.FileInfo) {
+	if ok, _ := filepath.Match(w.opts.Glob, filepath.Base(path)); ok || w.opts.Glob == "" {
+		w.s.PushBack(&Entry{Path: path, Depth: depth, Info: info})
+	}
+}
+
+func (w *dirWalker) walk(dir string, depth int) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		w.s.PushBack(&Entry{Path: dir, Depth: depth - 1, Err: err})
+		return
+	}
+	for _, info := range infos {
+		if w.stopped() {
+			return
+		}
+		path := filepath.Join(dir, info.Name())
+		if info.Mode()&os.ModeSymlink != 0 {
+			switch w.opts.Symlinks {
+			case SkipSymlinks:
+				continue
+			case IncludeSymlinks:
+				w.emit(path, depth, info)
+				continue
+			}
+			target, err := os.Stat(path)
+			if err != nil {
+				w.s.PushBack(&Entry{Path: path, Depth: depth, Info: info, Err: err})
+				continue
+			}
+			info = target
+		}
+		if !info.IsDir() {
+			w.emit(path, depth, info)
+			continue
+		}
+		if w.opts.Dirs {
+			w.emit(path, depth, info)
+		}
+		if w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth {
+			continue
+		}
+		if w.opts.Symlinks != FollowSymlinks || w.visit(path) {
+			w.walk(path, depth+1)
+		}
+	}
+}
+
+// FileLine is an element of FromGlobLines. Err is set, with No and Text
+// empty, when Path could not be read.
+type FileLine struct {
+	Path string
+	No   uint
+	Text string
+	Err  error
+}
+
+func (l *FileLine) String() string {
+	if l.Err != nil {
+		return fmt.Sprintf("%s: %s", l.Path, l.Err.Error())
+	}
+	return fmt.Sprintf("%s:%d: %s", l.Path, l.No, l.Text)
+}
+
+func FromGlobLines(pattern string) *Stream {
+	return FromGlobLinesContext(context.Background(), pattern)
+}
+
+// FromGlobLinesContext emits the lines of every regular file matching
+// pattern, one file after another in lexical order, as *FileLine values
+// numbered from 1. Files that fail part way are reported with a final
+// *FileLine carrying Err. Cancelling ctx or closing the stream stops
+// reading the current file.
+func FromGlobLinesContext(ctx context.Context, pattern string) *Stream {
+	s := New()
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return s.fail(err)
+	}
+	go func() {
+		stopped := func() bool { return ctx.Err() != nil || s.isClosed() }
+		for _, path := range paths {
+			if stopped() {
+				break
+			}
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				continue
+			}
+			lines := FromFileLines(path)
+			no := uint(0)
+			for x := lines.Pull(); x != EndMarker; x = lines.Pull() {
+				if stopped() {
+					lines.Close()
+					break
+				}
+				no++
+				s.PushBack(&FileLine{Path: path, No: no, Text: x.(string)})
+			}
+			if err := lines.Err(); err != nil {
+				s.PushBack(&FileLine{Path: path, Err: err})
+			}
+		}
+		s.fail(ctx.Err())
+	}()
+	return s
+}
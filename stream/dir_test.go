@@ -0,0 +1,122 @@
+package stream
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+// tree lays out root/a.txt, root/b.log, root/sub/c.txt and
+// root/sub/deep/d.txt and returns root.
+func tree(t *testing.T) string {
+	root := t.TempDir()
+	for path, content := range map[string]string{
+		"a.txt":          "a1\na2\n",
+		"b.log":          "b1\n",
+		"sub/c.txt":      "c1",
+		"sub/deep/d.txt": "d1\n",
+	} {
+		full := filepath.Join(root, path)
+		_ = os.MkdirAll(filepath.Dir(full), 0755)
+		if err := ioutil.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func paths(root string, s *Stream) *list.ConcurrentList {
+	return s.Map(func(x interface{}) interface{} {
+		e := x.(*Entry)
+		if e.Err != nil {
+			return "error"
+		}
+		rel, _ := filepath.Rel(root, e.Path)
+		return filepath.ToSlash(rel)
+	}).PullAll()
+}
+
+func TestStream_FromDir(t *testing.T) {
+	should := fStream("FromDir", t)
+	root := tree(t)
+	should("walk files in lexical order", list.New("a.txt", "b.log", "sub/c.txt", "sub/deep/d.txt"), func() interface{} {
+		return paths(root, FromDir(root, DirOptions{}))
+	})
+	should("filter by glob", list.New("a.txt", "sub/c.txt", "sub/deep/d.txt"), func() interface{} {
+		return paths(root, FromDir(root, DirOptions{Glob: "*.txt"}))
+	})
+	should("limit depth and include dirs", list.New("a.txt", "b.log", "sub", "sub/c.txt", "sub/deep"), func() interface{} {
+		return paths(root, FromDir(root, DirOptions{MaxDepth: 2, Dirs: true}))
+	})
+	should("report unreadable roots per entry", list.New("error"), func() interface{} {
+		return paths(root, FromDir(filepath.Join(root, "missing"), DirOptions{}))
+	})
+	should("refuse malformed globs", filepath.ErrBadPattern, func() interface{} {
+		return FromDir(root, DirOptions{Glob: "["}).Err()
+	})
+	should("stop when cancelled", context.Canceled, func() interface{} {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		s := FromDir(root, DirOptions{Context: ctx})
+		s.Consume()
+		return s.Err()
+	})
+}
+
+func TestStream_FromDirSymlinks(t *testing.T) {
+	should := fStream("FromDir", t)
+	root := tree(t)
+	if err := os.Symlink(filepath.Join(root, "sub"), filepath.Join(root, "link")); err != nil {
+		t.Skip("symlinks are not supported")
+	}
+	_ = os.Symlink(root, filepath.Join(root, "sub", "loop"))
+	should("skip symlinks by default", list.New("a.txt", "b.log", "sub/c.txt", "sub/deep/d.txt"), func() interface{} {
+		return paths(root, FromDir(root, DirOptions{}))
+	})
+	should("include symlinks without following", list.New("a.txt", "b.log", "link", "sub/c.txt", "sub/deep/d.txt", "sub/loop"), func() interface{} {
+		return paths(root, FromDir(root, DirOptions{Symlinks: IncludeSymlinks}))
+	})
+	should("follow symlinks once", list.New("a.txt", "b.log", "link/c.txt", "link/deep/d.txt"), func() interface{} {
+		return paths(root, FromDir(root, DirOptions{Symlinks: FollowSymlinks}))
+	})
+}
+
+func TestStream_FromGlobLines(t *testing.T) {
+	should := fStream("FromGlobLines", t)
+	root := tree(t)
+	should("tag lines with path and number", list.New("a.txt:1: a1", "a.txt:2: a2", "b.log:1: b1"), func() interface{} {
+		return FromGlobLines(filepath.Join(root, "*")).Map(func(x interface{}) interface{} {
+			l := x.(*FileLine)
+			l.Path = filepath.Base(l.Path)
+			return l.String()
+		}).PullAll()
+	})
+	should("stop when cancelled", context.Canceled, func() interface{} {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		s := FromGlobLinesContext(ctx, filepath.Join(root, "*.txt"))
+		s.Consume()
+		return s.Err()
+	})
+	should("stop reading the current file when closed", true, func() interface{} {
+		dir := t.TempDir()
+		if err := ioutil.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Repeat("x\n", 100000)), 0644); err != nil {
+			return err
+		}
+		before := runtime.NumGoroutine()
+		s := FromGlobLines(filepath.Join(dir, "*"))
+		s.Pull()
+		s.Close()
+		for i := 0; i < 1000 && runtime.NumGoroutine() > before; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		return runtime.NumGoroutine() <= before
+	})
+}
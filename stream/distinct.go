@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"github.com/nl253/DataStructures"
+	"github.com/nl253/DataStructures/cache"
+)
+
+func (s *Stream) Distinct() *Stream {
+	return s.DistinctBy(identity)
+}
+
+// DistinctBy drops elements whose key has been seen before. Keys must be
+// comparable and are all retained, see DistinctByN for bounded memory.
+func (s *Stream) DistinctBy(keyFn func(x interface{}) interface{}) *Stream {
+	seen := make(map[interface{}]bool)
+	return s.Filter(func(x interface{}) bool {
+		k := keyFn(x)
+		if seen[k] {
+			return false
+		}
+		seen[k] = true
+		return true
+	})
+}
+
+func (s *Stream) DistinctN(n uint) *Stream {
+	return s.DistinctByN(identity, n)
+}
+
+// DistinctByN remembers only the n most recently seen keys, so a duplicate
+// further back than that is let through again. n must be positive, use
+// DistinctBy for an unbounded set of keys.
+func (s *Stream) DistinctByN(keyFn func(x interface{}) interface{}, n uint) *Stream {
+	if n == 0 {
+		panic("[ERROR] DistinctByN needs to remember at least 1 key")
+	}
+	seen := cache.NewLRU(n)
+	return s.Filter(func(x interface{}) bool {
+		k := keyFn(x)
+		if _, ok := seen.Get(k); ok {
+			return false
+		}
+		seen.Set(k, true)
+		return true
+	})
+}
+
+// DistinctUntilChanged drops elements equal to the one before them, see
+// DataStructures.Eq.
+func (s *Stream) DistinctUntilChanged() *Stream {
+	return s.DistinctUntilChangedBy(DataStructures.Eq)
+}
+
+func (s *Stream) DistinctUntilChangedBy(eq func(x interface{}, y interface{}) bool) *Stream {
+	var prev interface{}
+	first := true
+	return s.Filter(func(x interface{}) bool {
+		if !first && eq(x, prev) {
+			return false
+		}
+		first = false
+		prev = x
+		return true
+	})
+}
+
+func identity(x interface{}) interface{} { return x }
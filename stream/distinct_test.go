@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+type caseless string
+
+func (c caseless) Eq(x interface{}) bool {
+	other, ok := x.(caseless)
+	return ok && strings.EqualFold(string(c), string(other))
+}
+
+func TestStream_Distinct(t *testing.T) {
+	should := fStream("Distinct", t)
+	should("drop repeated elements", list.New(1, 2, 3), func() interface{} {
+		return New(1, 2, 1, 3, 2, 1).Close().Distinct().PullAll()
+	})
+	should("keep everything when all elements differ", list.New(0, 1, 2), func() interface{} {
+		return Range(0, 3, 1).Distinct().PullAll()
+	})
+	should("generate valid stream", true, func() interface{} { return isValid(Range(0, 3, 1).Distinct()) })
+}
+
+func TestStream_DistinctBy(t *testing.T) {
+	should := fStream("DistinctBy", t)
+	should("drop elements with repeated keys", list.New("apple", "banana"), func() interface{} {
+		return New("apple", "avocado", "banana", "blueberry").Close().DistinctBy(func(x interface{}) interface{} { return x.(string)[0] }).PullAll()
+	})
+}
+
+func TestStream_DistinctN(t *testing.T) {
+	should := fStream("DistinctN", t)
+	should("forget keys beyond the bound", list.New(1, 2, 3, 1), func() interface{} {
+		return New(1, 2, 2, 3, 1).Close().DistinctN(2).PullAll()
+	})
+	should("refuse a bound of 0", true, func() (panicked interface{}) {
+		defer func() { panicked = recover() != nil }()
+		New(1, 1).Close().DistinctN(0)
+		return false
+	})
+}
+
+func TestStream_DistinctUntilChanged(t *testing.T) {
+	should := fStream("DistinctUntilChanged", t)
+	should("drop consecutive duplicates", list.New(1, 2, 1, 3), func() interface{} {
+		return New(1, 1, 2, 2, 2, 1, 3, 3).Close().DistinctUntilChanged().PullAll()
+	})
+	should("use Eq of Equatable elements", list.New(caseless("a"), caseless("b")), func() interface{} {
+		return New(caseless("a"), caseless("A"), caseless("b"), caseless("B")).Close().DistinctUntilChanged().PullAll()
+	})
+	should("use a custom equality", list.New(1, 5), func() interface{} {
+		return New(1, 2, 5, 6).Close().DistinctUntilChangedBy(func(x interface{}, y interface{}) bool {
+			return x.(int)-y.(int) < 2
+		}).PullAll()
+	})
+}
@@ -0,0 +1,111 @@
+package stream
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FanIn merges pushes from many producers into one stream which closes once
+// every registered producer is done, much like a sync.WaitGroup. Register
+// producers before starting them, otherwise the stream may close as soon as
+// the first one finishes.
+type FanIn struct {
+	s       *Stream
+	lk      *sync.Mutex
+	dedup   bool
+	active  uint
+	closed  bool
+	lastSeq map[interface{}]uint64
+}
+
+// Producer is the handle a single producer pushes through.
+type Producer struct {
+	id   interface{}
+	fi   *FanIn
+	done bool
+}
+
+// NewFanIn makes a FanIn, with dedup PushSeq drops every push whose
+// sequence number is not greater than the last one accepted for the same
+// producer id, so producers that retry after a failure are emitted once.
+func NewFanIn(dedup bool) *FanIn {
+	return &FanIn{
+		s:       New(),
+		lk:      &sync.Mutex{},
+		dedup:   dedup,
+		lastSeq: make(map[interface{}]uint64),
+	}
+}
+
+func (fi *FanIn) Stream() *Stream { return fi.s }
+
+// Register adds a producer. Producers registering with the same id share
+// sequence numbers, which is how a restarted producer resumes.
+func (fi *FanIn) Register(id interface{}) *Producer {
+	fi.lk.Lock()
+	defer fi.lk.Unlock()
+	if fi.closed {
+		panic(fmt.Sprintf("[ERROR] cannot register producer %v, all producers are done", id))
+	}
+	fi.active++
+	return &Producer{id: id, fi: fi}
+}
+
+// Active is the number of producers that have not called Done.
+func (fi *FanIn) Active() uint {
+	fi.lk.Lock()
+	defer fi.lk.Unlock()
+	return fi.active
+}
+
+func (p *Producer) Push(x interface{}) {
+	p.fi.lk.Lock()
+	defer p.fi.lk.Unlock()
+	p.check()
+	p.fi.s.PushBack(x)
+}
+
+// PushSeq pushes x unless de-duplication drops it and reports whether it
+// was pushed. Sequence numbers start at 1.
+func (p *Producer) PushSeq(seq uint64, x interface{}) bool {
+	p.fi.lk.Lock()
+	defer p.fi.lk.Unlock()
+	p.check()
+	if p.fi.dedup {
+		if seq <= p.fi.lastSeq[p.id] {
+			return false
+		}
+		p.fi.lastSeq[p.id] = seq
+	}
+	p.fi.s.PushBack(x)
+	return true
+}
+
+// LastSeq is the last sequence number accepted for the id of p.
+func (p *Producer) LastSeq() uint64 {
+	p.fi.lk.Lock()
+	defer p.fi.lk.Unlock()
+	return p.fi.lastSeq[p.id]
+}
+
+// Done retires p, calling it again does nothing. The last producer to
+// finish closes the stream.
+func (p *Producer) Done() {
+	p.fi.lk.Lock()
+	defer p.fi.lk.Unlock()
+	if p.done {
+		return
+	}
+	p.done = true
+	p.fi.active--
+	if p.fi.active == 0 {
+		p.fi.closed = true
+		p.fi.s.Close()
+	}
+}
+
+func (p *Producer) check() {
+	if p.done {
+		panic(fmt.Sprintf("[ERROR] producer %v pushed after Done", p.id))
+	}
+}
@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+const pushes uint = 1000
+
+func TestStream_FanIn(t *testing.T) {
+	should := fStream("FanIn", t)
+	should("close once every producer is done", uint(pushes*8), func() interface{} {
+		fi := NewFanIn(false)
+		producers := make([]*Producer, 8)
+		for i := range producers {
+			producers[i] = fi.Register(i)
+		}
+		for _, p := range producers {
+			go func(p *Producer) {
+				defer p.Done()
+				for i := uint(0); i < pushes; i++ {
+					p.Push(i)
+				}
+			}(p)
+		}
+		return fi.Stream().Count()
+	})
+	should("stay open while producers are active", []interface{}{uint(1), false}, func() interface{} {
+		fi := NewFanIn(false)
+		a, b := fi.Register("a"), fi.Register("b")
+		a.Push(1)
+		a.Done()
+		a.Done()
+		defer b.Done()
+		return []interface{}{fi.Active(), fi.Stream().isClosed()}
+	})
+	should("drop retried pushes", list.New("a1", "a2", "b1", "a3"), func() interface{} {
+		fi := NewFanIn(true)
+		a, b := fi.Register("a"), fi.Register("b")
+		a.PushSeq(1, "a1")
+		a.PushSeq(2, "a2")
+		b.PushSeq(1, "b1")
+		a.Done()
+		retry := fi.Register("a")
+		for seq := retry.LastSeq() - 1; seq <= 3; seq++ {
+			retry.PushSeq(seq, fmt.Sprintf("a%d", seq))
+		}
+		retry.Done()
+		b.Done()
+		return fi.Stream().PullAll()
+	})
+	should("deliver each sequence number once under contention", uint(pushes), func() interface{} {
+		fi := NewFanIn(true)
+		wg := sync.WaitGroup{}
+		producers := make([]*Producer, 4)
+		for i := range producers {
+			producers[i] = fi.Register("shared")
+		}
+		for _, p := range producers {
+			wg.Add(1)
+			go func(p *Producer) {
+				defer wg.Done()
+				defer p.Done()
+				for seq := uint64(1); seq <= uint64(pushes); seq++ {
+					p.PushSeq(seq, seq)
+				}
+			}(p)
+		}
+		wg.Wait()
+		return fi.Stream().Count()
+	})
+	should("refuse producers after closing", true, func() (panicked interface{}) {
+		fi := NewFanIn(false)
+		fi.Register(1).Done()
+		defer func() { panicked = recover() != nil }()
+		fi.Register(2)
+		return false
+	})
+}
@@ -0,0 +1,151 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nl253/DataStructures/clock"
+)
+
+func FollowFile(filePath string, poll time.Duration) *Stream {
+	return followFile(context.Background(), DefaultClock, filePath, poll)
+}
+
+// FollowFileWithClock is FollowFile polling on c, the returned stream uses c
+// as well.
+func FollowFileWithClock(c clock.Clock, filePath string, poll time.Duration) *Stream {
+	return followFile(context.Background(), c, filePath, poll)
+}
+
+// FollowFileContext is FromFileLines that, like tail -F, waits for more
+// lines at the end of the file instead of closing. The file is checked
+// every poll on DefaultClock. A file that shrinks is read again from
+// the start, and when filePath is replaced by a new file (log rotation) the
+// rest of the old one is emitted before the new one is opened. A line is
+// only emitted once its newline has been written, except for the last line
+// of a rotated file. Following stops when the stream is closed or ctx is
+// done, in which case Err reports ctx.Err().
+func FollowFileContext(ctx context.Context, filePath string, poll time.Duration) *Stream {
+	return followFile(ctx, DefaultClock, filePath, poll)
+}
+
+func followFile(ctx context.Context, c clock.Clock, filePath string, poll time.Duration) *Stream {
+	s := New().WithClock(c)
+	file, err := os.Open(filePath)
+	if err != nil {
+		return s.fail(err)
+	}
+	go func() {
+		f := &follower{s: s, clk: c, path: filePath, file: file, br: bufio.NewReader(file)}
+		err := f.follow(ctx, poll)
+		_ = f.file.Close()
+		s.fail(err)
+	}()
+	return s
+}
+
+type follower struct {
+	s       *Stream
+	clk     clock.Clock
+	path    string
+	file    *os.File
+	br      *bufio.Reader
+	offset  int64
+	partial string
+}
+
+func (f *follower) follow(ctx context.Context, poll time.Duration) error {
+	for {
+		if f.s.isClosed() {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := f.drain(); err != nil {
+			return err
+		}
+		rotated, err := f.check()
+		if err != nil {
+			return err
+		}
+		if rotated {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-f.clk.After(poll):
+		}
+	}
+}
+
+// drain emits every complete line up to the end of the file.
+func (f *follower) drain() error {
+	for {
+		chunk, err := f.br.ReadString('\n')
+		f.offset += int64(len(chunk))
+		f.partial += chunk
+		if strings.HasSuffix(f.partial, "\n") {
+			f.emit()
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (f *follower) emit() {
+	f.s.PushBack(strings.TrimSuffix(strings.TrimSuffix(f.partial, "\n"), "\r"))
+	f.partial = ""
+}
+
+// check handles truncation and rotation and reports whether there may be
+// more to read straight away.
+func (f *follower) check() (bool, error) {
+	current, err := os.Stat(f.path)
+	if os.IsNotExist(err) {
+		// Rotated away, the new file has not been created yet.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	opened, err := f.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if !os.SameFile(opened, current) {
+		file, err := os.Open(f.path)
+		if err != nil {
+			return false, nil
+		}
+		if err := f.drain(); err != nil {
+			_ = file.Close()
+			return false, err
+		}
+		if f.partial != "" {
+			f.emit()
+		}
+		_ = f.file.Close()
+		f.file, f.offset = file, 0
+		f.br.Reset(file)
+		return true, nil
+	}
+	if opened.Size() < f.offset {
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+		f.offset, f.partial = 0, ""
+		f.br.Reset(f.file)
+		return true, nil
+	}
+	return false, nil
+}
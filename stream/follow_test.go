@@ -0,0 +1,99 @@
+package stream
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nl253/DataStructures/clock"
+	"github.com/nl253/DataStructures/list"
+)
+
+const poll = 5 * time.Millisecond
+
+func appendTo(t *testing.T, path string, content string) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStream_FollowFile(t *testing.T) {
+	should := fStream("FollowFile", t)
+	should("emit existing lines", list.New("one", "two"), func() interface{} {
+		s := FollowFile(tempFile(t, "one\ntwo\n"), poll)
+		return list.New(s.Pull(), s.Pull())
+	})
+	should("stop when cancelled", []interface{}{EndMarker, context.Canceled}, func() interface{} {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := FollowFileContext(ctx, tempFile(t, ""), poll)
+		cancel()
+		return []interface{}{s.Pull(), s.Err()}
+	})
+	should("end with Err set for missing files", true, func() interface{} {
+		return FollowFile("missing.txt", poll).Err() != nil
+	})
+}
+
+func TestStream_FollowFileWithClock(t *testing.T) {
+	should := fStream("FollowFileWithClock", t)
+	should("emit appended lines on the next poll", list.New("one", "two", "three"), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		path := tempFile(t, "one\ntw")
+		s := FollowFileWithClock(v, path, poll)
+		first := s.Pull()
+		v.BlockUntil(1)
+		appendTo(t, path, "o\nthree\n")
+		v.Advance(poll)
+		return list.New(first, s.Pull(), s.Pull())
+	})
+	should("start over after truncation", list.New("old", "new"), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		path := tempFile(t, "old\n")
+		s := FollowFileWithClock(v, path, poll)
+		first := s.Pull()
+		v.BlockUntil(1)
+		if err := os.Truncate(path, 0); err != nil {
+			return err
+		}
+		v.Advance(poll)
+		v.BlockUntil(1)
+		appendTo(t, path, "new\n")
+		v.Advance(poll)
+		return list.New(first, s.Pull())
+	})
+	should("switch to the new file after rotation", list.New("a", "b", "c"), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		path := tempFile(t, "a\n")
+		s := FollowFileWithClock(v, path, poll)
+		first := s.Pull()
+		v.BlockUntil(1)
+		appendTo(t, path, "b")
+		if err := os.Rename(path, path+".1"); err != nil {
+			return err
+		}
+		appendTo(t, path, "c\n")
+		v.Advance(poll)
+		return list.New(first, s.Pull(), s.Pull())
+	})
+	should("stop when closed", EndMarker, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		path := tempFile(t, "a\n")
+		s := FollowFileWithClock(v, path, poll)
+		s.Pull()
+		v.BlockUntil(1)
+		s.Close()
+		appendTo(t, path, "b\n")
+		v.Advance(poll)
+		return s.Pull()
+	})
+	should("hand the clock to the returned stream", true, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		return FollowFileWithClock(v, tempFile(t, ""), poll).Clock() == v
+	})
+}
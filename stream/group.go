@@ -0,0 +1,59 @@
+package stream
+
+import "fmt"
+
+// Group is a keyed sub-stream produced by GroupBy.
+type Group struct {
+	Key    interface{}
+	Stream *Stream
+}
+
+func (g *Group) String() string {
+	return fmt.Sprintf("%v: %s", g.Key, g.Stream.String())
+}
+
+// GroupBy routes every element to the sub-stream of its key, a new *Group is
+// emitted the first time a key is seen. The source is read once and all
+// groups end, with its error, when it ends. Groups buffer independently, so a group
+// that is never consumed does not hold up the others.
+func (s *Stream) GroupBy(keyFn func(x interface{}) interface{}) *Stream {
+	newS := s.derive()
+	go func() {
+		groups := make(map[interface{}]*Stream)
+		order := make([]*Stream, 0)
+		s.forEach(func(x interface{}) {
+			k := keyFn(x)
+			g, ok := groups[k]
+			if !ok {
+				g = s.derive()
+				groups[k] = g
+				order = append(order, g)
+				newS.PushBack(&Group{Key: k, Stream: g})
+			}
+			g.PushBack(x)
+		})
+		for _, g := range order {
+			g.fail(s.Err())
+		}
+		newS.fail(s.Err())
+	}()
+	return newS
+}
+
+// Partition splits s in two: elements satisfying pred and the rest.
+func (s *Stream) Partition(pred func(x interface{}) bool) (*Stream, *Stream) {
+	yes := s.derive()
+	no := s.derive()
+	go func() {
+		s.forEach(func(x interface{}) {
+			if pred(x) {
+				yes.PushBack(x)
+			} else {
+				no.PushBack(x)
+			}
+		})
+		yes.fail(s.Err())
+		no.fail(s.Err())
+	}()
+	return yes, no
+}
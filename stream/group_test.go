@@ -0,0 +1,42 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+func TestStream_GroupBy(t *testing.T) {
+	should := fStream("GroupBy", t)
+	mod3 := func(x interface{}) interface{} { return x.(int) % 3 }
+	should("emit a group per key in order of first appearance", list.New(0, 1, 2), func() interface{} {
+		return Range(0, 10, 1).GroupBy(mod3).Map(func(g interface{}) interface{} { return g.(*Group).Key }).PullAll()
+	})
+	should("route elements to their group", list.New(list.New(0, 3, 6, 9), list.New(1, 4, 7), list.New(2, 5, 8)).String(), func() interface{} {
+		return Range(0, 10, 1).GroupBy(mod3).Map(func(g interface{}) interface{} { return g.(*Group).Stream.PullAll() }).PullAll().String()
+	})
+	should("let groups be consumed out of order", list.New(2, 5, 8), func() interface{} {
+		groups := Range(0, 10, 1).GroupBy(mod3).PullAll()
+		return groups.Nth(2).(*Group).Stream.PullAll()
+	})
+	should("group nothing for an empty stream", list.New(), func() interface{} { return Range(0, 0, 1).GroupBy(mod3).PullAll() })
+}
+
+func TestStream_Partition(t *testing.T) {
+	should := fStream("Partition", t)
+	should("split by predicate", list.New(list.New(0, 2, 4), list.New(1, 3)).String(), func() interface{} {
+		even, odd := Range(0, 5, 1).Partition(func(x interface{}) bool { return x.(int)%2 == 0 })
+		return list.New(even.PullAll(), odd.PullAll()).String()
+	})
+	should("generate valid streams", true, func() interface{} {
+		yes, no := Range(0, 5, 1).Partition(func(x interface{}) bool { return true })
+		return isValid(yes) && isValid(no)
+	})
+}
+
+func TestStream_Group(t *testing.T) {
+	should := fStream("Group", t)
+	should("show key and buffered elements", "a: |1 < 2|", func() interface{} {
+		return (&Group{Key: "a", Stream: New(1, 2)}).String()
+	})
+}
@@ -0,0 +1,217 @@
+package stream
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Overflow decides what a Hub does when a subscriber's buffer is full.
+type Overflow int
+
+const (
+	DropNewest Overflow = iota
+	DropOldest
+	// Disconnect unsubscribes, and so closes, subscribers that fall behind.
+	Disconnect
+)
+
+type SubOptions struct {
+	// Buffer is how many undelivered messages a subscriber may have, 0
+	// means no limit.
+	Buffer   uint
+	Overflow Overflow
+}
+
+// Message is what subscribers of a Hub receive.
+type Message struct {
+	Topic string
+	Val   interface{}
+}
+
+func (m *Message) String() string {
+	return fmt.Sprintf("%s: %v", m.Topic, m.Val)
+}
+
+// Hub routes published values to every subscriber whose pattern matches the
+// topic, see TopicMatch. Each subscriber has its own buffer so a slow one
+// never holds up publishers or other subscribers.
+type Hub struct {
+	lk    *sync.Mutex
+	subs  map[*Stream]*subscriber
+	order []*Stream
+	// left keeps the drop counts of disconnected subscribers until read.
+	left   map[*Stream]uint64
+	closed bool
+}
+
+type subscriber struct {
+	pattern []string
+	opts    SubOptions
+	dropped uint64
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		lk:    &sync.Mutex{},
+		subs:  make(map[*Stream]*subscriber),
+		order: make([]*Stream, 0),
+		left:  make(map[*Stream]uint64),
+	}
+}
+
+// Subscribe returns a stream of *Message values published to topics
+// matching pattern. It is closed by Unsubscribe or Hub.Close, closing it
+// directly unsubscribes it as well.
+func (h *Hub) Subscribe(pattern string, opts SubOptions) *Stream {
+	segments := splitTopic(pattern)
+	for i, seg := range segments {
+		if seg == "" || (strings.ContainsAny(seg, "*#") && len(seg) > 1) {
+			panic(fmt.Sprintf("[ERROR] invalid topic pattern %q, segment %d", pattern, i))
+		}
+	}
+	s := New()
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	if h.closed {
+		return s.Close()
+	}
+	h.subs[s] = &subscriber{pattern: segments, opts: opts}
+	h.order = append(h.order, s)
+	return s
+}
+
+// Unsubscribe closes s and reports whether it was subscribed.
+func (h *Hub) Unsubscribe(s *Stream) bool {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	return h.unsubscribe(s)
+}
+
+func (h *Hub) unsubscribe(s *Stream) bool {
+	if _, ok := h.subs[s]; !ok {
+		return false
+	}
+	delete(h.subs, s)
+	for i, sub := range h.order {
+		if sub == s {
+			h.order = append(h.order[:i], h.order[i+1:]...)
+			break
+		}
+	}
+	s.Close()
+	return true
+}
+
+// prune unsubscribes streams that were closed directly.
+func (h *Hub) prune() {
+	for _, s := range append([]*Stream(nil), h.order...) {
+		if s.isClosed() {
+			h.unsubscribe(s)
+		}
+	}
+}
+
+// Publish delivers x to matching subscribers and reports how many got it.
+// Topics may not contain wildcards.
+func (h *Hub) Publish(topic string, x interface{}) uint {
+	if strings.ContainsAny(topic, "*#") {
+		panic(fmt.Sprintf("[ERROR] cannot publish to pattern %q", topic))
+	}
+	segments := splitTopic(topic)
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	h.prune()
+	delivered := uint(0)
+	for _, s := range append([]*Stream(nil), h.order...) {
+		sub := h.subs[s]
+		if !matchSegments(sub.pattern, segments) {
+			continue
+		}
+		if sub.opts.Buffer > 0 && s.BufSize() >= sub.opts.Buffer {
+			sub.dropped++
+			switch sub.opts.Overflow {
+			case DropNewest:
+				continue
+			case DropOldest:
+				s.dropFront()
+			case Disconnect:
+				h.unsubscribe(s)
+				h.left[s] = sub.dropped
+				continue
+			}
+		}
+		s.PushBack(&Message{Topic: topic, Val: x})
+		delivered++
+	}
+	return delivered
+}
+
+// Dropped counts messages s lost to its overflow policy. Counts are
+// forgotten on Unsubscribe, except that a subscriber cut off by Disconnect
+// can have its count read once afterwards.
+func (h *Hub) Dropped(s *Stream) uint64 {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	if sub, ok := h.subs[s]; ok {
+		return sub.dropped
+	}
+	n := h.left[s]
+	delete(h.left, s)
+	return n
+}
+
+func (h *Hub) Subscribers() uint {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	h.prune()
+	return uint(len(h.order))
+}
+
+// Close unsubscribes everyone, later subscriptions are closed straight away.
+func (h *Hub) Close() {
+	h.lk.Lock()
+	defer h.lk.Unlock()
+	h.closed = true
+	for len(h.order) > 0 {
+		h.unsubscribe(h.order[0])
+	}
+}
+
+func (h *Hub) String() string {
+	return fmt.Sprintf("Hub(%d subscribers)", h.Subscribers())
+}
+
+// TopicMatch matches dot separated topics where, in pattern, * stands for
+// exactly one segment and # for any number of segments, including none.
+func TopicMatch(pattern string, topic string) bool {
+	return matchSegments(splitTopic(pattern), splitTopic(topic))
+}
+
+func splitTopic(topic string) []string {
+	return strings.Split(topic, ".")
+}
+
+func matchSegments(pattern []string, topic []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case "#":
+			for i := 0; i <= len(topic); i++ {
+				if matchSegments(pattern[1:], topic[i:]) {
+					return true
+				}
+			}
+			return false
+		case "*":
+		default:
+			if len(topic) == 0 || pattern[0] != topic[0] {
+				return false
+			}
+		}
+		if len(topic) == 0 {
+			return false
+		}
+		pattern, topic = pattern[1:], topic[1:]
+	}
+	return len(topic) == 0
+}
@@ -0,0 +1,126 @@
+package stream
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+func messages(s *Stream) *list.ConcurrentList {
+	return s.Map(func(x interface{}) interface{} { return x.(*Message).String() }).PullAll()
+}
+
+func TestStream_TopicMatch(t *testing.T) {
+	should := fStream("TopicMatch", t)
+	cases := []struct {
+		pattern string
+		topic   string
+	}{
+		{"a.b", "a.b"}, {"a.*", "a.b"}, {"a.*", "a"}, {"a.*", "a.b.c"},
+		{"a.#", "a"}, {"a.#", "a.b.c"}, {"#.c", "a.b.c"}, {"a.#.c", "a.c"}, {"*.#", ""}, {"#", "x"},
+	}
+	should("match segments and wildcards", []bool{true, true, false, false, true, true, true, true, true, true}, func() interface{} {
+		got := make([]bool, len(cases))
+		for i, c := range cases {
+			got[i] = TopicMatch(c.pattern, c.topic)
+		}
+		return got
+	})
+}
+
+func TestStream_Hub(t *testing.T) {
+	should := fStream("Hub", t)
+	should("route messages by pattern", []interface{}{list.New("logs.app: 1", "logs.db: 2"), list.New("logs.db: 2", "metrics.db: 3")}, func() interface{} {
+		h := NewHub()
+		logs := h.Subscribe("logs.*", SubOptions{})
+		db := h.Subscribe("#.db", SubOptions{})
+		h.Publish("logs.app", 1)
+		h.Publish("logs.db", 2)
+		h.Publish("metrics.db", 3)
+		h.Close()
+		return []interface{}{messages(logs), messages(db)}
+	})
+	should("only deliver after subscribing and until unsubscribing", []interface{}{list.New("t: 2"), uint(0)}, func() interface{} {
+		h := NewHub()
+		h.Publish("t", 1)
+		s := h.Subscribe("t", SubOptions{})
+		h.Publish("t", 2)
+		h.Unsubscribe(s)
+		return []interface{}{messages(s), h.Publish("t", 3)}
+	})
+	should("drop the newest messages when full", []interface{}{list.New("t: 1", "t: 2"), uint64(1)}, func() interface{} {
+		h := NewHub()
+		s := h.Subscribe("t", SubOptions{Buffer: 2, Overflow: DropNewest})
+		for i := 1; i <= 3; i++ {
+			h.Publish("t", i)
+		}
+		dropped := h.Dropped(s)
+		h.Close()
+		return []interface{}{messages(s), dropped}
+	})
+	should("drop the oldest messages when full", list.New("t: 2", "t: 3"), func() interface{} {
+		h := NewHub()
+		s := h.Subscribe("t", SubOptions{Buffer: 2, Overflow: DropOldest})
+		for i := 1; i <= 3; i++ {
+			h.Publish("t", i)
+		}
+		h.Close()
+		return messages(s)
+	})
+	should("disconnect slow subscribers without holding up others", []interface{}{list.New("t: 1"), uint(3)}, func() interface{} {
+		h := NewHub()
+		slow := h.Subscribe("t", SubOptions{Buffer: 1, Overflow: Disconnect})
+		fast := h.Subscribe("t", SubOptions{})
+		for i := 1; i <= 3; i++ {
+			h.Publish("t", i)
+		}
+		h.Close()
+		return []interface{}{messages(slow), fast.Count()}
+	})
+	should("keep the drop count of disconnected subscribers until read", []uint64{1, 0}, func() interface{} {
+		h := NewHub()
+		slow := h.Subscribe("t", SubOptions{Buffer: 1, Overflow: Disconnect})
+		h.Publish("t", 1)
+		h.Publish("t", 2)
+		return []uint64{h.Dropped(slow), h.Dropped(slow)}
+	})
+	should("forget drop counts on Unsubscribe", []interface{}{uint64(1), uint64(0), 0}, func() interface{} {
+		h := NewHub()
+		s := h.Subscribe("t", SubOptions{Buffer: 1, Overflow: DropNewest})
+		h.Publish("t", 1)
+		h.Publish("t", 2)
+		before := h.Dropped(s)
+		h.Unsubscribe(s)
+		return []interface{}{before, h.Dropped(s), len(h.left)}
+	})
+	should("forget subscribers closed directly", []interface{}{uint(1), uint(1)}, func() interface{} {
+		h := NewHub()
+		h.Subscribe("t", SubOptions{}).Close()
+		h.Subscribe("t", SubOptions{})
+		return []interface{}{h.Publish("t", 1), h.Subscribers()}
+	})
+	should("refuse malformed patterns", true, func() (panicked interface{}) {
+		defer func() { panicked = recover() != nil }()
+		NewHub().Subscribe("a.b*", SubOptions{})
+		return false
+	})
+	should("be safe for concurrent use", uint(4*pushes), func() interface{} {
+		h := NewHub()
+		s := h.Subscribe("#", SubOptions{})
+		wg := sync.WaitGroup{}
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := uint(0); i < pushes; i++ {
+					h.Publish("a.b", i)
+					h.Unsubscribe(h.Subscribe("a", SubOptions{}))
+				}
+			}()
+		}
+		wg.Wait()
+		h.Close()
+		return s.Count()
+	})
+}
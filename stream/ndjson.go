@@ -0,0 +1,27 @@
+package stream
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/nl253/DataStructures/ndjson"
+)
+
+// FromJSONLines decodes one value of typ per line of r, see ndjson.Decoder.
+// With ndjson.FailFast the first malformed line ends the stream and is
+// reported by Err.
+func FromJSONLines(r io.Reader, typ reflect.Type, policy ndjson.Policy) *Stream {
+	s := New()
+	go func() {
+		d := ndjson.NewDecoder(r, typ, policy)
+		for x, ok := d.Next(); ok; x, ok = d.Next() {
+			s.PushBack(x)
+		}
+		s.fail(d.Err())
+	}()
+	return s
+}
+
+func (s *Stream) ToJSONLines(w io.Writer) (int64, error) {
+	return s.ToWriter(w, ndjson.Encode)
+}
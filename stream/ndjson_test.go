@@ -0,0 +1,43 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nl253/DataStructures/list"
+	"github.com/nl253/DataStructures/ndjson"
+)
+
+type event struct {
+	ID int `json:"id"`
+}
+
+func TestStream_FromJSONLines(t *testing.T) {
+	should := fStream("FromJSONLines", t)
+	input := "{\"id\": 1}\nnope\n{\"id\": 2}\n"
+	should("skip malformed lines", list.New(event{1}, event{2}), func() interface{} {
+		return FromJSONLines(strings.NewReader(input), reflect.TypeOf(event{}), ndjson.Skip).PullAll()
+	})
+	should("end with Err on malformed lines with FailFast", []interface{}{uint(1), true}, func() interface{} {
+		s := FromJSONLines(strings.NewReader(input), reflect.TypeOf(event{}), ndjson.FailFast)
+		n := s.Count()
+		var lineErr *ndjson.LineError
+		return []interface{}{n, errors.As(s.Err(), &lineErr) && lineErr.Line == 2}
+	})
+	should("emit malformed lines as errors", "nope", func() interface{} {
+		xs := FromJSONLines(strings.NewReader(input), reflect.TypeOf(event{}), ndjson.Emit).PullAll()
+		return xs.Nth(1).(*ndjson.LineError).Text
+	})
+}
+
+func TestStream_ToJSONLines(t *testing.T) {
+	should := fStream("ToJSONLines", t)
+	should("write one value per line", "{\"id\":1}\n{\"id\":2}\n", func() interface{} {
+		var buf bytes.Buffer
+		_, _ = New(event{1}, event{2}).Close().ToJSONLines(&buf)
+		return buf.String()
+	})
+}
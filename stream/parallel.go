@@ -0,0 +1,133 @@
+package stream
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// PanicError ends the output of ParallelMap, and is reported by its Err,
+// when the mapped function panics.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in mapped function: %v", e.Value)
+}
+
+// ParallelMap applies f on at most workers goroutines at once and emits the
+// results in input order.
+func (s *Stream) ParallelMap(workers uint, f func(x interface{}) interface{}) *Stream {
+	return s.parallelMap(workers, f, true)
+}
+
+// ParallelMapUnordered is like ParallelMap but emits results as soon as they
+// are ready.
+func (s *Stream) ParallelMapUnordered(workers uint, f func(x interface{}) interface{}) *Stream {
+	return s.parallelMap(workers, f, false)
+}
+
+type parallelJob struct {
+	seq uint64
+	x   interface{}
+}
+
+type parallelResult struct {
+	seq uint64
+	x   interface{}
+	err *PanicError
+}
+
+func (s *Stream) parallelMap(workers uint, f func(x interface{}) interface{}, ordered bool) *Stream {
+	if workers == 0 {
+		panic("[ERROR] ParallelMap needs at least 1 worker")
+	}
+	newS := s.derive()
+	jobs := make(chan parallelJob)
+	results := make(chan parallelResult, workers)
+	// inflight bounds how far the dispatcher runs ahead of the collector, so
+	// the reorder buffer never holds more than workers results
+	inflight := make(chan struct{}, workers)
+	failed := int32(0)
+	wg := &sync.WaitGroup{}
+	wg.Add(int(workers))
+	for i := uint(0); i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- apply(f, j)
+			}
+		}()
+	}
+	go func() {
+		seq := uint64(0)
+		for atomic.LoadInt32(&failed) == 0 {
+			x := s.Pull()
+			if x == EndMarker {
+				break
+			}
+			inflight <- struct{}{}
+			if atomic.LoadInt32(&failed) != 0 {
+				<-inflight
+				s.PushFront(x)
+				break
+			}
+			jobs <- parallelJob{seq: seq, x: x}
+			seq++
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+	go func() {
+		next := uint64(0)
+		pending := make(map[uint64]parallelResult)
+		var panicErr error
+		// failed is set before the result's slot is released so that the
+		// dispatcher, once unblocked, sees it and puts its element back
+		emit := func(r parallelResult) {
+			defer func() { <-inflight }()
+			if atomic.LoadInt32(&failed) != 0 {
+				return
+			}
+			if r.err != nil {
+				atomic.StoreInt32(&failed, 1)
+				panicErr = r.err
+				return
+			}
+			newS.PushBack(r.x)
+		}
+		for r := range results {
+			if !ordered {
+				emit(r)
+				continue
+			}
+			pending[r.seq] = r
+			for ready, ok := pending[next]; ok; ready, ok = pending[next] {
+				delete(pending, next)
+				emit(ready)
+				next++
+			}
+		}
+		if panicErr != nil {
+			newS.fail(panicErr)
+		} else {
+			newS.fail(s.Err())
+		}
+	}()
+	return newS
+}
+
+func apply(f func(x interface{}) interface{}, j parallelJob) (r parallelResult) {
+	r.seq = j.seq
+	defer func() {
+		if v := recover(); v != nil {
+			r.err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	r.x = f(j.x)
+	return r
+}
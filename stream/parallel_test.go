@@ -0,0 +1,81 @@
+package stream
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+func TestStream_ParallelMap(t *testing.T) {
+	should := fStream("ParallelMap", t)
+	should("preserve input order", Range(1, 101, 1).PullAll(), func() interface{} {
+		return Range(0, 100, 1).ParallelMap(8, func(x interface{}) interface{} {
+			time.Sleep(time.Duration(100-x.(int)) * time.Microsecond)
+			return x.(int) + 1
+		}).PullAll()
+	})
+	should("map 0 elements", list.New(), func() interface{} {
+		return Range(0, 0, 1).ParallelMap(4, func(x interface{}) interface{} { return x }).PullAll()
+	})
+	should("never run more than workers at once", true, func() interface{} {
+		running := int32(0)
+		peak := int32(0)
+		Range(0, 50, 1).ParallelMap(3, func(x interface{}) interface{} {
+			n := atomic.AddInt32(&running, 1)
+			for p := atomic.LoadInt32(&peak); n > p && !atomic.CompareAndSwapInt32(&peak, p, n); p = atomic.LoadInt32(&peak) {
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return x
+		}).Consume()
+		return atomic.LoadInt32(&peak) <= 3
+	})
+	should("fail with a PanicError when f panics", []interface{}{list.New(0, 1, 2, 3, 4), "boom"}, func() interface{} {
+		out := Range(0, 10, 1).ParallelMap(2, func(x interface{}) interface{} {
+			if x.(int) == 5 {
+				panic("boom")
+			}
+			return x
+		})
+		xs := out.PullAll()
+		return []interface{}{xs, out.Err().(*PanicError).Value}
+	})
+	should("leave elements it did not dispatch in the source", 1, func() interface{} {
+		src := New(0, 1, 2).Close()
+		src.ParallelMap(1, func(x interface{}) interface{} { panic(x) }).Consume()
+		return src.Pull()
+	})
+	should("carry the source error", errFlaky, func() interface{} {
+		out := failing(1, 2).ParallelMap(2, func(x interface{}) interface{} { return x })
+		out.Consume()
+		return out.Err()
+	})
+	should("generate valid stream", true, func() interface{} {
+		return isValid(Range(0, 10, 1).ParallelMap(2, func(x interface{}) interface{} { return x }))
+	})
+}
+
+func TestStream_ParallelMapUnordered(t *testing.T) {
+	should := fStream("ParallelMapUnordered", t)
+	should("map every element", float64(5050), func() interface{} {
+		return Range(0, 100, 1).ParallelMapUnordered(8, func(x interface{}) interface{} { return x.(int) + 1 }).Sum()
+	})
+	should("emit results as they complete", 1, func() interface{} {
+		return Range(0, 2, 1).ParallelMapUnordered(2, func(x interface{}) interface{} {
+			if x.(int) == 0 {
+				time.Sleep(100 * time.Millisecond)
+			}
+			return x
+		}).Pull()
+	})
+	should("fail with a PanicError when f panics", []interface{}{uint(0), true}, func() interface{} {
+		out := Range(0, 10, 1).ParallelMapUnordered(2, func(x interface{}) interface{} {
+			panic(x)
+		})
+		n := out.Count()
+		_, ok := out.Err().(*PanicError)
+		return []interface{}{n, ok}
+	})
+}
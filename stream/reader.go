@@ -0,0 +1,119 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// FromReader emits the bytes of r, read through a buffer. Read errors other
+// than io.EOF end the stream and are reported by Err.
+func FromReader(r io.Reader) *Stream {
+	return fromReader(r, nil, readBytes)
+}
+
+// FromReaderLines emits the lines of r without line endings, following
+// bufio.ScanLines.
+func FromReaderLines(r io.Reader) *Stream {
+	return FromReaderSplit(r, bufio.ScanLines)
+}
+
+// FromReaderRunes decodes r as UTF-8, invalid input becomes utf8.RuneError.
+func FromReaderRunes(r io.Reader) *Stream {
+	return fromReader(r, nil, readRunes)
+}
+
+// FromReaderSplit emits the tokens produced by split as strings, see
+// bufio.Scanner.
+func FromReaderSplit(r io.Reader, split bufio.SplitFunc) *Stream {
+	return fromReader(r, nil, readSplit(split))
+}
+
+// fromFile opens filePath, decompressing by extension, and closes it once
+// read has finished with it. Failing to open the file gives a closed stream
+// with Err set.
+func fromFile(filePath string, read func(io.Reader, *Stream) error) *Stream {
+	return fromFileCompressed(filePath, AutoCompression, read)
+}
+
+func fromFileCompressed(filePath string, c Compression, read func(io.Reader, *Stream) error) *Stream {
+	r, closer, err := openFile(filePath, c)
+	if err != nil {
+		return New().fail(err)
+	}
+	return fromReader(r, closer, read)
+}
+
+// fromReader runs read in the background, read stops once s is closed so
+// that abandoning a stream also stops reading r.
+func fromReader(r io.Reader, c io.Closer, read func(io.Reader, *Stream) error) *Stream {
+	s := New()
+	go func() {
+		err := read(r, s)
+		if c != nil {
+			if closeErr := c.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		s.fail(err)
+	}()
+	return s
+}
+
+func readBytes(r io.Reader, s *Stream) error {
+	br := bufio.NewReader(r)
+	for !s.isClosed() {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.PushBack(b)
+	}
+	return nil
+}
+
+func readRunes(r io.Reader, s *Stream) error {
+	br := bufio.NewReader(r)
+	for !s.isClosed() {
+		c, _, err := br.ReadRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.PushBack(c)
+	}
+	return nil
+}
+
+func readSplit(split bufio.SplitFunc) func(io.Reader, *Stream) error {
+	return func(r io.Reader, s *Stream) error {
+		scanner := bufio.NewScanner(r)
+		scanner.Split(split)
+		for !s.isClosed() && scanner.Scan() {
+			s.PushBack(scanner.Text())
+		}
+		return scanner.Err()
+	}
+}
+
+// splitOn is a bufio.SplitFunc cutting at every delim, the final token is
+// emitted even without a trailing delim.
+func splitOn(delim byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.IndexByte(data, delim); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
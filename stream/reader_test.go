@@ -0,0 +1,103 @@
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+func tempFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestStream_FromReader(t *testing.T) {
+	should := fStream("FromReader", t)
+	should("emit bytes", list.New(byte('a'), byte('b')), func() interface{} {
+		return FromReader(strings.NewReader("ab")).PullAll()
+	})
+	should("survive one-byte reads", uint(100), func() interface{} {
+		return FromReader(iotest.OneByteReader(strings.NewReader(strings.Repeat("x", 100)))).Count()
+	})
+	should("report read errors through Err", []interface{}{list.New(byte('a')), iotest.ErrTimeout}, func() interface{} {
+		s := FromReader(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("ab"))))
+		return []interface{}{s.PullAll(), s.Err()}
+	})
+	should("generate valid stream", true, func() interface{} { return isValid(FromReader(strings.NewReader("abc"))) })
+}
+
+// endless yields "x\n" forever.
+type endless struct{}
+
+func (endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = "x\n"[i%2]
+	}
+	return len(p), nil
+}
+
+func TestStream_FromReaderLines(t *testing.T) {
+	should := fStream("FromReaderLines", t)
+	should("emit lines without endings", list.New("a", "b", "", "c"), func() interface{} {
+		return FromReaderLines(strings.NewReader("a\r\nb\n\nc")).PullAll()
+	})
+	should("report tokens that are too long", bufio.ErrTooLong, func() interface{} {
+		s := FromReaderLines(strings.NewReader(strings.Repeat("x", bufio.MaxScanTokenSize+1)))
+		s.Consume()
+		return s.Err()
+	})
+	should("stop reading once closed", true, func() interface{} {
+		before := runtime.NumGoroutine()
+		s := FromReaderLines(endless{})
+		s.Pull()
+		s.Close()
+		for i := 0; i < 1000 && runtime.NumGoroutine() > before; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		return runtime.NumGoroutine() <= before
+	})
+}
+
+func TestStream_FromReaderRunes(t *testing.T) {
+	should := fStream("FromReaderRunes", t)
+	should("decode UTF-8", list.New('z', 'ó', 'ł', 'w'), func() interface{} {
+		return FromReaderRunes(strings.NewReader("zółw")).PullAll()
+	})
+}
+
+func TestStream_FromReaderSplit(t *testing.T) {
+	should := fStream("FromReaderSplit", t)
+	should("split with a bufio.SplitFunc", list.New("a", "bc", "d"), func() interface{} {
+		return FromReaderSplit(strings.NewReader(" a  bc\td\n"), bufio.ScanWords).PullAll()
+	})
+}
+
+func TestStream_FromFileSplit(t *testing.T) {
+	should := fStream("FromFileSplit", t)
+	should("split on a delimiter and keep the last field", list.New("a", "", "b"), func() interface{} {
+		return FromFileSplit(tempFile(t, "a,,b"), ',').PullAll()
+	})
+	should("end with Err set for missing files", true, func() interface{} {
+		s := FromFileSplit(filepath.Join(t.TempDir(), "missing"), ',')
+		return s.PullAll().Empty() && errors.Is(s.Err(), os.ErrNotExist)
+	})
+}
+
+func TestStream_FromFileLines(t *testing.T) {
+	should := fStream("FromFileLines", t)
+	should("emit lines", list.New("one", "two"), func() interface{} {
+		return FromFileLines(tempFile(t, "one\ntwo\n")).PullAll()
+	})
+}
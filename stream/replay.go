@@ -0,0 +1,101 @@
+package stream
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Replayer drains a stream into a window of retained elements so that any
+// number of subscribers, attached at any time, can read it.
+type Replayer struct {
+	lk      *sync.Mutex
+	cond    *sync.Cond
+	src     *Stream
+	n       uint
+	buf     []interface{}
+	evicted uint64
+	done    bool
+}
+
+// Replay retains the last n elements of s, Cache retains all of them.
+// Elements are retained as they arrive, whether or not anyone subscribed.
+func (s *Stream) Replay(n uint) *Replayer {
+	lk := &sync.Mutex{}
+	r := &Replayer{lk: lk, cond: sync.NewCond(lk), src: s, n: n, buf: make([]interface{}, 0)}
+	go func() {
+		s.forEach(func(x interface{}) {
+			r.lk.Lock()
+			r.buf = append(r.buf, x)
+			if r.n > 0 && uint(len(r.buf)) > r.n {
+				r.buf[0] = nil
+				r.buf = r.buf[1:]
+				r.evicted++
+			}
+			r.lk.Unlock()
+			r.cond.Broadcast()
+		})
+		r.lk.Lock()
+		r.done = true
+		r.lk.Unlock()
+		r.cond.Broadcast()
+	}()
+	return r
+}
+
+func (s *Stream) Cache() *Replayer { return s.Replay(0) }
+
+// Subscribe returns a stream starting at the oldest retained element and
+// following new ones, it ends when the source does. Elements are handed
+// over one at a time as the subscriber pulls them, so a subscriber that
+// falls behind the window skips what was evicted in the meantime.
+func (r *Replayer) Subscribe() *Stream {
+	newS := r.src.derive()
+	r.lk.Lock()
+	cursor := r.evicted
+	r.lk.Unlock()
+	go func() {
+		for {
+			r.lk.Lock()
+			for cursor >= r.evicted+uint64(len(r.buf)) && !r.done {
+				r.cond.Wait()
+			}
+			if cursor < r.evicted {
+				cursor = r.evicted
+			}
+			if cursor >= r.evicted+uint64(len(r.buf)) {
+				r.lk.Unlock()
+				newS.fail(r.src.Err())
+				return
+			}
+			x := r.buf[cursor-r.evicted]
+			cursor++
+			r.lk.Unlock()
+			if newS.isClosed() {
+				return
+			}
+			newS.PushBack(x)
+			newS.awaitDrained()
+		}
+	}()
+	return newS
+}
+
+// Len is the number of retained elements.
+func (r *Replayer) Len() uint {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return uint(len(r.buf))
+}
+
+// Done reports whether the source has ended.
+func (r *Replayer) Done() bool {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return r.done
+}
+
+func (r *Replayer) String() string {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	return fmt.Sprintf("Replayer(%d retained, done=%v)", len(r.buf), r.done)
+}
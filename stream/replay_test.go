@@ -0,0 +1,80 @@
+package stream
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+func TestStream_Replay(t *testing.T) {
+	should := fStream("Replay", t)
+	should("give late subscribers the last n elements", list.New(7, 8, 9), func() interface{} {
+		r := Ints(0, 10).Replay(3)
+		r.Subscribe().Consume()
+		return r.Subscribe().PullAll()
+	})
+	should("give every subscriber an independent cursor", []interface{}{1, list.New(2, 3), list.New(1, 2, 3)}, func() interface{} {
+		r := New(1, 2).Cache()
+		first := r.Subscribe()
+		second := r.Subscribe()
+		head := first.Pull()
+		r.src.PushBack(3)
+		r.src.Close()
+		return []interface{}{head, first.PullAll(), second.PullAll()}
+	})
+	should("follow elements that arrive after subscribing", list.New("a", "b"), func() interface{} {
+		src := New()
+		s := src.Cache().Subscribe()
+		src.PushBack("a")
+		src.PushBack("b")
+		src.Close()
+		return s.PullAll()
+	})
+	should("skip elements evicted while a subscriber was slow", list.New(1, 5, 6), func() interface{} {
+		src := New()
+		r := src.Replay(2)
+		slow := r.Subscribe()
+		src.PushBack(1)
+		slow.PeekFront()
+		for i := 2; i <= 6; i++ {
+			src.PushBack(i)
+		}
+		src.Close()
+		r.Subscribe().Consume()
+		return slow.PullAll()
+	})
+	should("hold at most one element per subscriber", uint(1), func() interface{} {
+		r := Ints(0, 100).Cache()
+		s := r.Subscribe()
+		r.Subscribe().Consume()
+		s.PeekFront()
+		return s.BufSize()
+	})
+	should("carry the source error", iotest.ErrTimeout, func() interface{} {
+		s := FromReader(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("ab")))).Cache().Subscribe()
+		s.Consume()
+		return s.Err()
+	})
+	should("serve concurrent subscribers", true, func() interface{} {
+		r := Ints(0, int(pushes)).Cache()
+		wg := sync.WaitGroup{}
+		ok := make([]bool, 8)
+		for i := range ok {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ok[i] = r.Subscribe().Count() == pushes
+			}(i)
+		}
+		wg.Wait()
+		for _, b := range ok {
+			if !b {
+				return false
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,170 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+var ErrTimeout = errors.New("stream: timed out")
+
+// RetryPolicy describes exponential backoff with jitter. The zero value
+// tries once.
+type RetryPolicy struct {
+	// Retries is how many times to try again after the first failure.
+	Retries uint
+	// Initial is the wait before the first retry, each later one waits
+	// Multiplier (2 when unset) times longer, up to Max when it is set.
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// Jitter in [0, 1] shortens each wait by up to that fraction.
+	Jitter float64
+	// Rand returns numbers in [0, 1) for jitter, math/rand when nil.
+	Rand func() float64
+	// Retryable picks which errors are worth retrying, all when nil.
+	Retryable func(err error) bool
+}
+
+// Backoff is the wait before retry number attempt, counting from 1, and 0
+// is taken as 1. Waits are capped at Max, or at the longest Duration when
+// Max is unset.
+func (p RetryPolicy) Backoff(attempt uint) time.Duration {
+	if attempt == 0 {
+		attempt = 1
+	}
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	limit := float64(math.MaxInt64)
+	if p.Max > 0 {
+		limit = float64(p.Max)
+	}
+	d := float64(p.Initial) * math.Pow(multiplier, float64(attempt-1))
+	if d > limit || math.IsNaN(d) {
+		d = limit
+	}
+	if p.Jitter > 0 {
+		random := p.Rand
+		if random == nil {
+			random = rand.Float64
+		}
+		d -= d * p.Jitter * random()
+	}
+	if d >= float64(math.MaxInt64) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+// RetryError is what ends a MapWithRetry stream once an element has used up
+// its retries.
+type RetryError struct {
+	Val      interface{}
+	Attempts uint
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("stream: %v failed after %d attempts - %s", e.Val, e.Attempts, e.Err.Error())
+}
+
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// MapWithRetry is Map for functions that can fail. Failed calls are retried
+// according to policy, waiting on the stream clock. An element that keeps
+// failing ends the stream with a *RetryError reported by Err.
+func (s *Stream) MapWithRetry(f func(x interface{}) (interface{}, error), policy RetryPolicy) *Stream {
+	newS := s.derive()
+	go func() {
+		for x := s.Pull(); x != EndMarker; x = s.Pull() {
+			for attempt := uint(1); ; attempt++ {
+				y, err := f(x)
+				if err == nil {
+					newS.PushBack(y)
+					break
+				}
+				if attempt > policy.Retries || (policy.Retryable != nil && !policy.Retryable(err)) {
+					newS.fail(&RetryError{Val: x, Attempts: attempt, Err: err})
+					return
+				}
+				s.clk.Sleep(policy.Backoff(attempt))
+			}
+		}
+		newS.fail(s.Err())
+	}()
+	return newS
+}
+
+// Timeout ends the stream with ErrTimeout when the next element takes
+// longer than d to arrive.
+func (s *Stream) Timeout(d time.Duration) *Stream {
+	return s.timeout(d, true)
+}
+
+// TimeoutStream ends the stream with ErrTimeout unless s ends within d.
+func (s *Stream) TimeoutStream(d time.Duration) *Stream {
+	return s.timeout(d, false)
+}
+
+func (s *Stream) timeout(d time.Duration, perElement bool) *Stream {
+	newS := s.derive()
+	done := make(chan struct{})
+	xs := s.channelUntil(done)
+	go func() {
+		timer := s.clk.NewTimer(d)
+		defer func() {
+			timer.Stop()
+			close(done)
+		}()
+		for {
+			select {
+			case x, ok := <-xs:
+				if !ok {
+					newS.fail(s.Err())
+					return
+				}
+				newS.PushBack(x)
+				if perElement {
+					timer.Stop()
+					timer = s.clk.NewTimer(d)
+				}
+			case <-timer.Chan():
+				newS.fail(ErrTimeout)
+				return
+			}
+		}
+	}()
+	return newS
+}
+
+// OnErrorResume continues with fallback when s ends with an error.
+func (s *Stream) OnErrorResume(fallback *Stream) *Stream {
+	newS := s.derive()
+	go func() {
+		s.forEach(newS.PushBack)
+		if s.Err() == nil {
+			newS.Close()
+			return
+		}
+		fallback.forEach(newS.PushBack)
+		newS.fail(fallback.Err())
+	}()
+	return newS
+}
+
+// OnErrorReturn emits x in place of the error when s ends with one.
+func (s *Stream) OnErrorReturn(x interface{}) *Stream {
+	newS := s.derive()
+	go func() {
+		s.forEach(newS.PushBack)
+		if s.Err() != nil {
+			newS.PushBack(x)
+		}
+		newS.Close()
+	}()
+	return newS
+}
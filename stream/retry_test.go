@@ -0,0 +1,196 @@
+package stream
+
+import (
+	"errors"
+	"io"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/nl253/DataStructures/clock"
+	"github.com/nl253/DataStructures/list"
+)
+
+var errFlaky = errors.New("flaky")
+
+// flaky fails the first n calls for every element.
+func flaky(n int) func(x interface{}) (interface{}, error) {
+	calls := make(map[interface{}]int)
+	return func(x interface{}) (interface{}, error) {
+		calls[x]++
+		if calls[x] <= n {
+			return nil, errFlaky
+		}
+		return x.(int) * 10, nil
+	}
+}
+
+func failing(xs ...interface{}) *Stream {
+	s := New(xs...)
+	return s.fail(errFlaky)
+}
+
+func TestStream_Backoff(t *testing.T) {
+	should := fStream("Backoff", t)
+	should("grow exponentially up to max", []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond, time.Second}, func() interface{} {
+		p := RetryPolicy{Initial: 100 * time.Millisecond, Multiplier: 3, Max: time.Second}
+		return []time.Duration{p.Backoff(1), p.Backoff(2), p.Backoff(3), p.Backoff(4)}
+	})
+	should("treat attempt 0 as the first attempt", time.Second, func() interface{} {
+		return RetryPolicy{Initial: time.Second}.Backoff(0)
+	})
+	should("not overflow without max", []time.Duration{time.Duration(math.MaxInt64), time.Minute}, func() interface{} {
+		return []time.Duration{RetryPolicy{Initial: time.Second}.Backoff(100), RetryPolicy{Initial: time.Second, Max: time.Minute}.Backoff(100)}
+	})
+	should("shorten waits by jitter", 150*time.Millisecond, func() interface{} {
+		p := RetryPolicy{Initial: 200 * time.Millisecond, Jitter: 0.5, Rand: func() float64 { return 0.5 }}
+		return p.Backoff(1)
+	})
+}
+
+func TestStream_MapWithRetry(t *testing.T) {
+	should := fStream("MapWithRetry", t)
+	policy := RetryPolicy{Retries: 2, Initial: time.Second}
+	should("retry failures after backing off", []interface{}{list.New(10, 20), 6 * time.Second}, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		out := New(1, 2).Close().WithClock(v).MapWithRetry(flaky(2), policy)
+		for i := 0; i < 2; i++ {
+			v.BlockUntil(1)
+			v.Advance(time.Second)
+			v.BlockUntil(1)
+			v.Advance(2 * time.Second)
+		}
+		return []interface{}{out.PullAll(), v.Now().Sub(epoch)}
+	})
+	should("end with a RetryError once retries run out", []interface{}{list.New(), uint(3), true}, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		out := New(1).Close().WithClock(v).MapWithRetry(flaky(3), policy)
+		v.BlockUntil(1)
+		v.Advance(time.Second)
+		v.BlockUntil(1)
+		v.Advance(2 * time.Second)
+		xs := out.PullAll()
+		return []interface{}{xs, attempts(out.Err()), errors.Is(out.Err(), errFlaky)}
+	})
+	should("not retry errors that are not retryable", uint(1), func() interface{} {
+		p := policy
+		p.Retryable = func(err error) bool { return err != errFlaky }
+		out := New(1).Close().MapWithRetry(flaky(1), p)
+		out.Consume()
+		return attempts(out.Err())
+	})
+}
+
+func attempts(err error) uint {
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		return 0
+	}
+	return retryErr.Attempts
+}
+
+func TestStream_Timeout(t *testing.T) {
+	should := fStream("Timeout", t)
+	should("fail when an element is late", []interface{}{1, EndMarker, ErrTimeout}, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New().WithClock(v)
+		out := s.Timeout(time.Second)
+		s.PushBack(1)
+		fst := out.Pull()
+		v.BlockUntilCreated(2)
+		v.Advance(time.Second)
+		return []interface{}{fst, out.Pull(), out.Err()}
+	})
+	should("pass streams that keep up", []interface{}{1, list.New(2), nil}, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New().WithClock(v)
+		out := s.Timeout(time.Second)
+		s.PushBack(1)
+		fst := out.Pull()
+		v.BlockUntilCreated(2)
+		v.Advance(999 * time.Millisecond)
+		s.PushBack(2)
+		s.Close()
+		return []interface{}{fst, out.PullAll(), out.Err()}
+	})
+}
+
+func TestStream_TimeoutLeak(t *testing.T) {
+	should := fStream("Timeout", t)
+	should("stop pumping the source once timed out", true, func() interface{} {
+		before := runtime.NumGoroutine()
+		v := clock.NewVirtual(epoch)
+		srcs := make([]*Stream, 50)
+		outs := make([]*Stream, len(srcs))
+		for i := range srcs {
+			srcs[i] = New().WithClock(v)
+			outs[i] = srcs[i].Timeout(time.Second)
+		}
+		v.BlockUntil(len(srcs))
+		v.Advance(time.Second)
+		for _, out := range outs {
+			out.Consume()
+		}
+		for _, src := range srcs {
+			src.PushBack(1)
+			src.Close()
+		}
+		for i := 0; i < 1000 && runtime.NumGoroutine() > before; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		return runtime.NumGoroutine() <= before
+	})
+}
+
+func TestStream_TimeoutStream(t *testing.T) {
+	should := fStream("TimeoutStream", t)
+	should("fail when the stream takes too long overall", []interface{}{list.New(1, 2), EndMarker, ErrTimeout}, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New(1).WithClock(v)
+		out := s.TimeoutStream(time.Second)
+		v.BlockUntil(1)
+		v.Advance(500 * time.Millisecond)
+		s.PushBack(2)
+		got := list.New(out.Pull(), out.Pull())
+		v.Advance(500 * time.Millisecond)
+		return []interface{}{got, out.Pull(), out.Err()}
+	})
+}
+
+func TestStream_OnErrorResume(t *testing.T) {
+	should := fStream("OnErrorResume", t)
+	should("continue with the fallback", []interface{}{list.New(1, 2, 3), nil}, func() interface{} {
+		out := failing(1).OnErrorResume(New(2, 3).Close())
+		return []interface{}{out.PullAll(), out.Err()}
+	})
+	should("ignore the fallback without errors", list.New(1), func() interface{} {
+		return New(1).Close().OnErrorResume(New(2).Close()).PullAll()
+	})
+}
+
+func TestStream_OnErrorReturn(t *testing.T) {
+	should := fStream("OnErrorReturn", t)
+	should("replace the error with a value", []interface{}{list.New(1, "default"), nil}, func() interface{} {
+		out := failing(1).OnErrorReturn("default")
+		return []interface{}{out.PullAll(), out.Err()}
+	})
+	should("recover from exhausted retries", list.New(10, -1), func() interface{} {
+		return New(1, 2).Close().MapWithRetry(func(x interface{}) (interface{}, error) {
+			if x == 2 {
+				return nil, errFlaky
+			}
+			return x.(int) * 10, nil
+		}, RetryPolicy{}).OnErrorReturn(-1).PullAll()
+	})
+	should("see errors through Map and Filter", list.New("A", "fallback"), func() interface{} {
+		r := io.MultiReader(strings.NewReader("a\nb\n"), iotest.ErrReader(errFlaky))
+		return FromReaderLines(r).
+			Map(func(x interface{}) interface{} { return strings.ToUpper(x.(string)) }).
+			Filter(func(x interface{}) bool { return x != "B" }).
+			OnErrorReturn("fallback").
+			PullAll()
+	})
+}
@@ -1,6 +1,7 @@
 package stream
 
 import (
+	"bufio"
 	"fmt"
 	"io"
 	"math/rand"
@@ -9,6 +10,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/nl253/DataStructures/clock"
+	"github.com/nl253/DataStructures/hyperloglog"
 	"github.com/nl253/DataStructures/list"
 )
 
@@ -18,8 +21,15 @@ type Stream struct {
 	lksLk  *sync.Mutex
 	buf    *list.ConcurrentList
 	lks    *list.ConcurrentList
+	clk    clock.Clock
+	err    error
+	// drained is signalled, with bufLk, whenever buf becomes empty.
+	drained *sync.Cond
 }
 
+// DefaultClock is the clock given to new streams, see WithClock.
+var DefaultClock = clock.Real()
+
 type streamEnd struct{}
 
 var EndMarker = &streamEnd{}
@@ -38,12 +48,15 @@ func (e *streamEnd) Eq(x interface{}) bool {
 }
 
 func New(xs ...interface{}) *Stream {
+	bufLk := &sync.Mutex{}
 	s := &Stream{
-		bufLk:  &sync.Mutex{},
-		lksLk:  &sync.Mutex{},
-		buf:    list.New(),
-		lks:    list.New(),
-		closed: false,
+		bufLk:   bufLk,
+		lksLk:   &sync.Mutex{},
+		buf:     list.New(),
+		lks:     list.New(),
+		closed:  false,
+		clk:     DefaultClock,
+		drained: sync.NewCond(bufLk),
 	}
 	for _, x := range xs {
 		s.PushBack(x)
@@ -81,43 +94,15 @@ func FromSliceSubslice(s []interface{}, n uint) *Stream {
 }
 
 func FromFile(filePath string) *Stream {
-	file, e := os.Open(filePath)
-	if e != nil {
-		panic(fmt.Sprintf("[ERROR] failed to open file - %s", e.Error()))
-	}
-	s := New()
-	buf := make([]byte, 1, 1)
-	off := int64(0)
-	go func() {
-		for _, err := file.ReadAt(buf, off); err != io.EOF; _, err = file.ReadAt(buf, off) {
-			s.PushBack(buf[0])
-			off++
-		}
-		s.Close()
-	}()
-	return s
+	return fromFile(filePath, readBytes)
 }
 
 func FromFileSplit(filePath string, delim byte) *Stream {
-	s := FromFile(filePath)
-	newS := New()
-	go func() {
-		sb := strings.Builder{}
-		for !s.Closed() {
-			if b := s.Pull().(byte); b == delim {
-				newS.PushBack(sb.String())
-				sb = strings.Builder{}
-			} else {
-				sb.WriteByte(b)
-			}
-		}
-		s.Close()
-	}()
-	return newS
+	return fromFile(filePath, readSplit(splitOn(delim)))
 }
 
 func FromFileLines(filePath string) *Stream {
-	return FromFileSplit(filePath, '\n')
+	return fromFile(filePath, readSplit(bufio.ScanLines))
 }
 
 func FromStr(s string) *Stream {
@@ -203,16 +188,39 @@ func Repeat(n uint, x interface{}) *Stream {
 }
 
 func Tick(freq time.Duration, n uint, x interface{}) *Stream {
-	return Emit(freq, n, func(_ uint) interface{} { return x })
+	return TickWithClock(DefaultClock, freq, n, x)
+}
+
+// TickWithClock is Tick timed by c, the returned stream uses c as well.
+func TickWithClock(c clock.Clock, freq time.Duration, n uint, x interface{}) *Stream {
+	return EmitWithClock(c, freq, n, func(_ uint) interface{} { return x })
 }
 
 func Emit(freq time.Duration, count uint, f func(n uint) interface{}) *Stream {
-	return Nats(count).Map(func(x interface{}) interface{} {
-		time.Sleep(freq)
+	return EmitWithClock(DefaultClock, freq, count, f)
+}
+
+// EmitWithClock is Emit timed by c, the returned stream uses c as well.
+func EmitWithClock(c clock.Clock, freq time.Duration, count uint, f func(n uint) interface{}) *Stream {
+	return Nats(count).WithClock(c).Map(func(x interface{}) interface{} {
+		c.Sleep(freq)
 		return f(x.(uint))
 	})
 }
 
+// WithClock sets the clock used by time-aware operators on s, streams they
+// return inherit it.
+func (s *Stream) WithClock(c clock.Clock) *Stream {
+	s.clk = c
+	return s
+}
+
+func (s *Stream) Clock() clock.Clock { return s.clk }
+
+func (s *Stream) derive() *Stream {
+	return New().WithClock(s.clk)
+}
+
 func (s *Stream) PushFront(t interface{}) {
 	s.bufLk.Lock()
 	s.buf.PushFront(t)
@@ -244,17 +252,22 @@ func (s *Stream) Pull() interface{} {
 				s.bufLk.Unlock()
 				return EndMarker
 			}
-			s.bufLk.Unlock()
+			// register before releasing bufLk, otherwise a Close in between
+			// would miss this waiter and leave it blocked forever
 			l := &sync.Mutex{}
 			l.Lock()
 			s.lksLk.Lock()
 			s.lks.PushBack(l)
 			s.lksLk.Unlock()
+			s.bufLk.Unlock()
 			l.Lock()
 			l.Unlock()
 			continue
 		}
 		front := s.buf.PopFront()
+		if s.buf.Empty() {
+			s.drained.Broadcast()
+		}
 		s.bufLk.Unlock()
 		return front
 	}
@@ -300,17 +313,65 @@ func (s *Stream) PeekFront() interface{} {
 }
 
 func (s *Stream) Close() *Stream {
+	s.bufLk.Lock()
 	s.closed = true
 	s.lksLk.Lock()
-	s.bufLk.Lock()
 	s.lks.ForEach(func(l interface{}, _ uint) {
 		l.(*sync.Mutex).Unlock()
 	})
-	s.bufLk.Unlock()
+	s.lks.Clear()
 	s.lksLk.Unlock()
+	s.drained.Broadcast()
+	s.bufLk.Unlock()
 	return s
 }
 
+// dropFront discards the oldest buffered element, if any.
+func (s *Stream) dropFront() {
+	s.bufLk.Lock()
+	if !s.buf.Empty() {
+		s.buf.PopFront()
+	}
+	if s.buf.Empty() {
+		s.drained.Broadcast()
+	}
+	s.bufLk.Unlock()
+}
+
+// awaitDrained blocks until everything pushed so far has been pulled or s
+// is closed, which lets producers hand over one element at a time.
+func (s *Stream) awaitDrained() {
+	s.bufLk.Lock()
+	for !s.buf.Empty() && !s.closed {
+		s.drained.Wait()
+	}
+	s.bufLk.Unlock()
+}
+
+func (s *Stream) isClosed() bool {
+	s.bufLk.Lock()
+	defer s.bufLk.Unlock()
+	return s.closed
+}
+
+// Err reports why a source stopped early, it is nil for streams that ended
+// normally or are still open.
+func (s *Stream) Err() error {
+	s.bufLk.Lock()
+	defer s.bufLk.Unlock()
+	return s.err
+}
+
+// fail records err, if any, and closes s.
+func (s *Stream) fail(err error) *Stream {
+	if err != nil {
+		s.bufLk.Lock()
+		s.err = err
+		s.bufLk.Unlock()
+	}
+	return s.Close()
+}
+
 func (s *Stream) forEach(f func(x interface{})) {
 	for x := s.Pull(); x != EndMarker; x = s.Pull() {
 		f(x)
@@ -318,10 +379,10 @@ func (s *Stream) forEach(f func(x interface{})) {
 }
 
 func (s *Stream) Map(f func(x interface{}) interface{}) *Stream {
-	newS := New()
+	newS := s.derive()
 	go func() {
 		s.forEach(func(x interface{}) { newS.PushBack(f(x)) })
-		newS.Close()
+		newS.fail(s.Err())
 	}()
 	return newS
 }
@@ -373,33 +434,35 @@ func (s *Stream) Sprintf(format string) *Stream {
 
 func (s *Stream) Throttle(d time.Duration) *Stream {
 	return s.Map(func(x interface{}) interface{} {
-		time.Sleep(d)
+		s.clk.Sleep(d)
 		return x
 	})
 }
 
 func (s *Stream) Spike(n uint, d time.Duration) *Stream {
-	newS := New()
+	newS := s.derive()
 	go func() {
 		for !s.Closed() {
-			time.Sleep(d)
-			i := uint(0)
-			for x := s.Pull(); i < n && x != EndMarker; x = s.Pull() {
+			s.clk.Sleep(d)
+			for i := uint(0); i < n; i++ {
+				x := s.Pull()
+				if x == EndMarker {
+					break
+				}
 				newS.PushBack(x)
-				i++
 			}
 		}
-		newS.Close()
+		newS.fail(s.Err())
 	}()
 	return newS
 }
 
 func (s *Stream) Delay(d time.Duration) *Stream {
-	newS := New()
+	newS := s.derive()
 	go func() {
-		time.Sleep(d)
+		s.clk.Sleep(d)
 		s.forEach(func(x interface{}) { newS.PushBack(x) })
-		newS.Close()
+		newS.fail(s.Err())
 	}()
 	return newS
 }
@@ -415,36 +478,36 @@ func (s *Stream) Broadcast(ss ...*Stream) *Stream {
 func (s *Stream) Tee(n uint) []*Stream {
 	ss := make([]*Stream, n)
 	for i := uint(0); i < n; i++ {
-		ss[i] = New()
+		ss[i] = s.derive()
 	}
 	s.Broadcast(ss...)
 	return ss
 }
 
 func (s *Stream) Filter(f func(x interface{}) bool) *Stream {
-	newS := New()
+	newS := s.derive()
 	go func() {
 		s.forEach(func(x interface{}) {
 			if f(x) {
 				newS.PushBack(x)
 			}
 		})
-		newS.Close()
+		newS.fail(s.Err())
 	}()
 	return newS
 }
 
 func (s *Stream) Flatten() *Stream {
-	newS := New()
+	newS := s.derive()
 	go func() {
 		s.forEach(func(innerStream interface{}) { innerStream.(*Stream).Pipe(newS) })
-		newS.Close()
+		newS.fail(s.Err())
 	}()
 	return newS
 }
 
 func (s *Stream) FlattenDeep() *Stream {
-	newS := New()
+	newS := s.derive()
 	go func() {
 		s.forEach(func(x interface{}) {
 			switch x.(type) {
@@ -454,7 +517,7 @@ func (s *Stream) FlattenDeep() *Stream {
 				newS.PushBack(x)
 			}
 		})
-		newS.Close()
+		newS.fail(s.Err())
 	}()
 	return newS
 }
@@ -464,12 +527,17 @@ func (s *Stream) FlatMap(f func(x interface{}) *Stream) *Stream {
 }
 
 func (s *Stream) TakeUntil(f func(x interface{}) bool) *Stream {
-	newS := New()
+	newS := s.derive()
 	go func() {
-		for x := s.Pull(); x != EndMarker && f(x); x = s.Pull() {
+		x := s.Pull()
+		for ; x != EndMarker && f(x); x = s.Pull() {
 			newS.PushBack(x)
 		}
-		newS.Close()
+		if x == EndMarker {
+			newS.fail(s.Err())
+		} else {
+			newS.Close()
+		}
 	}()
 	return newS
 }
@@ -481,7 +549,7 @@ func (s *Stream) TakeWhile(f func(x interface{}) bool) *Stream {
 func (s *Stream) Pipe(other *Stream) *Stream {
 	go func() {
 		s.forEach(func(x interface{}) { other.PushBack(x) })
-		other.Close()
+		other.fail(s.Err())
 	}()
 	return other
 }
@@ -489,12 +557,13 @@ func (s *Stream) Pipe(other *Stream) *Stream {
 func (s *Stream) Closed() bool { return s.PeekFront() == EndMarker }
 
 func (s *Stream) Take(n uint) *Stream {
-	newS := New()
+	newS := s.derive()
 	go func() {
 		for i := uint(0); i < n; i++ {
 			x := s.Pull()
 			if x == EndMarker {
-				break
+				newS.fail(s.Err())
+				return
 			}
 			newS.PushBack(x)
 		}
@@ -508,7 +577,7 @@ func (s *Stream) Skip() *Stream {
 }
 
 func (s *Stream) SkipN(n uint) *Stream {
-	newS := New()
+	newS := s.derive()
 	go func() {
 		s.Take(n).PullN(n)
 		s.Pipe(newS)
@@ -532,6 +601,10 @@ func (s *Stream) Count() uint {
 	return s.Reduce(uint(0), func(x interface{}, y interface{}) interface{} { return x.(uint) + 1 }).(uint)
 }
 
+func (s *Stream) CountDistinctApprox(precision uint8) uint64 {
+	return s.Reduce(hyperloglog.New(precision), hyperloglog.Reducer).(*hyperloglog.HyperLogLog).Count()
+}
+
 func (s *Stream) Sum() float64 {
 	switch s.PeekFront().(type) {
 	case int:
@@ -551,15 +624,6 @@ func (s *Stream) Sum() float64 {
 	}
 }
 
-func (s *Stream) Concat() string {
-	return s.Reduce("", func(x interface{}, y interface{}) interface{} { return x.(string) + y.(string) }).(string)
-}
-
-func (s *Stream) Join(delim string) string {
-	str := s.Reduce("", func(x interface{}, y interface{}) interface{} { return x.(string) + delim + y.(string) }).(string)
-	return str
-}
-
 func (s *Stream) BufSize() uint {
 	s.bufLk.Lock()
 	size := s.buf.Size()
@@ -580,6 +644,7 @@ func (s *Stream) BufDrain() *list.ConcurrentList {
 	s.bufLk.Lock()
 	saveList := s.buf.TakeWhile(func(x interface{}) bool { return x != EndMarker })
 	s.buf.Clear()
+	s.drained.Broadcast()
 	s.bufLk.Unlock()
 	return saveList
 }
@@ -598,12 +663,16 @@ func (s *Stream) Clone() *Stream {
 	s.bufLk.Lock()
 	defer s.lksLk.Unlock()
 	defer s.bufLk.Unlock()
+	bufLk := &sync.Mutex{}
 	return &Stream{
-		closed: s.closed,
-		bufLk:  &sync.Mutex{},
-		lksLk:  &sync.Mutex{},
-		buf:    s.buf.Clone(),
-		lks:    s.lks.Clone(),
+		closed:  s.closed,
+		err:     s.err,
+		bufLk:   bufLk,
+		lksLk:   &sync.Mutex{},
+		buf:     s.buf.Clone(),
+		lks:     s.lks.Clone(),
+		clk:     s.clk,
+		drained: sync.NewCond(bufLk),
 	}
 }
 
@@ -613,9 +682,9 @@ func (s *Stream) String() string {
 	s.buf.ForEach(func(x interface{}, idx uint) {
 		switch x.(type) {
 		case fmt.Stringer:
-			parts[idx] = x.(fmt.Stringer).String()
+			parts = append(parts, x.(fmt.Stringer).String())
 		default:
-			parts[idx] = fmt.Sprintf("%v", x)
+			parts = append(parts, fmt.Sprintf("%v", x))
 		}
 	})
 	s.bufLk.Unlock()
@@ -35,3 +35,23 @@ func BenchmarkStream_PushFront(b *testing.B) {
 		s.PushFront(i)
 	}
 }
+
+func work(x interface{}) interface{} {
+	acc := x.(uint)
+	for i := 0; i < 10000; i++ {
+		acc = acc*31 + uint(i)
+	}
+	return acc
+}
+
+func BenchmarkStream_Map(b *testing.B) {
+	Nats(uint(b.N)).Map(work).Consume()
+}
+
+func BenchmarkStream_ParallelMap(b *testing.B) {
+	Nats(uint(b.N)).ParallelMap(8, work).Consume()
+}
+
+func BenchmarkStream_ParallelMapUnordered(b *testing.B) {
+	Nats(uint(b.N)).ParallelMapUnordered(8, work).Consume()
+}
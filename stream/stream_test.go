@@ -3,6 +3,7 @@ package stream
 import (
 	"fmt"
 	"math/rand"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -87,6 +88,40 @@ func TestStream_Concurrency(t *testing.T) {
 	}
 }
 
+func TestStream_Close(t *testing.T) {
+	should := fStream("Close", t)
+	should("wake every Pull racing with it", true, func() interface{} {
+		for i := 0; i < 1000; i++ {
+			ss := New()
+			done := make(chan interface{})
+			for j := 0; j < 10; j++ {
+				go func() { done <- ss.Pull() }()
+			}
+			runtime.Gosched()
+			ss.Close()
+			for j := 0; j < 10; j++ {
+				select {
+				case x := <-done:
+					if x != EndMarker {
+						return false
+					}
+				case <-time.After(time.Second):
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+func TestStream_String(t *testing.T) {
+	should := fStream("String", t)
+	should("show an empty buffer", "||", func() interface{} { return New().String() })
+	should("show buffered elements oldest first", "|1 < a < [1]|", func() interface{} {
+		return New(1, "a", list.New(1)).String()
+	})
+}
+
 func TestStream_Range(t *testing.T) {
 	should := fStream("Range", t)
 	should("range puts ints from [min, max) to stream", list.New(1, 2, 3), func() interface{} { return Range(1, 4, 1).PullAll() })
@@ -135,6 +170,10 @@ func TestStream_Map(t *testing.T) {
 	should("generate valid stream", true, func() interface{} {
 		return isValid(Range(0, 0, 1).Map(func(x interface{}) interface{} { return x.(int) + 1 }))
 	})
+	should("carry the source error", []interface{}{list.New(2), errFlaky}, func() interface{} {
+		out := failing(1).Map(func(x interface{}) interface{} { return x.(int) + 1 })
+		return []interface{}{out.PullAll(), out.Err()}
+	})
 }
 
 func TestStream_RandF32s(t *testing.T) {
@@ -246,7 +285,7 @@ func TestStream_FromStr(t *testing.T) {
 func TestStream_FromFile(t *testing.T) {
 	should := fStream("FromFile", t)
 	ff := func() *Stream {
-		return FromFile("/home/mx/go/src/github.com/nl253/DataStructures/stream/stream_test.go")
+		return FromFile("stream_test.go")
 	}
 	should("make stream of bytes", true, func() interface{} {
 		return ff().PullAll().All(func(x interface{}) bool {
@@ -269,3 +308,11 @@ func TestStream_FromFile(t *testing.T) {
 		return sb.String()
 	})
 }
+
+func TestStream_CountDistinctApprox(t *testing.T) {
+	should := fStream("CountDistinctApprox", t)
+	should("count distinct elems", uint64(3), func() interface{} {
+		return New(1, 2, 3, 1, 2, 3).Close().CountDistinctApprox(12)
+	})
+	should("count 0 distinct elems", uint64(0), func() interface{} { return Nats(0).CountDistinctApprox(12) })
+}
@@ -0,0 +1,157 @@
+package stream
+
+import (
+	"math"
+	"time"
+)
+
+// Debounce emits an element only once d has passed without another one
+// arriving. A pending element is flushed when the stream ends.
+func (s *Stream) Debounce(d time.Duration) *Stream {
+	newS := s.derive()
+	go func() {
+		xs := s.channel()
+		var pending interface{}
+		hasPending := false
+		var timeout <-chan time.Time
+		var stop func() bool
+		for {
+			select {
+			case x, ok := <-xs:
+				if !ok {
+					if hasPending {
+						newS.PushBack(pending)
+					}
+					if stop != nil {
+						stop()
+					}
+					newS.fail(s.Err())
+					return
+				}
+				if stop != nil {
+					stop()
+				}
+				t := s.clk.NewTimer(d)
+				timeout, stop = t.Chan(), t.Stop
+				pending, hasPending = x, true
+			case <-timeout:
+				newS.PushBack(pending)
+				pending, hasPending = nil, false
+				timeout, stop = nil, nil
+			}
+		}
+	}()
+	return newS
+}
+
+// Sample emits the latest element every d, provided a new one arrived since
+// the previous tick. Like Debounce, a pending element is flushed when the
+// stream ends.
+func (s *Stream) Sample(d time.Duration) *Stream {
+	newS := s.derive()
+	go func() {
+		xs := s.channel()
+		var latest interface{}
+		fresh := false
+		tick := s.clk.NewTimer(d)
+		for {
+			select {
+			case x, ok := <-xs:
+				if !ok {
+					if fresh {
+						newS.PushBack(latest)
+					}
+					tick.Stop()
+					newS.fail(s.Err())
+					return
+				}
+				latest, fresh = x, true
+			case <-tick.Chan():
+				if fresh {
+					newS.PushBack(latest)
+					fresh = false
+				}
+				tick = s.clk.NewTimer(d)
+			}
+		}
+	}()
+	return newS
+}
+
+// ThrottleFirst emits an element and then drops everything arriving within
+// the next d.
+func (s *Stream) ThrottleFirst(d time.Duration) *Stream {
+	newS := s.derive()
+	go func() {
+		var last time.Time
+		first := true
+		s.forEach(func(x interface{}) {
+			if now := s.clk.Now(); first || now.Sub(last) >= d {
+				first = false
+				last = now
+				newS.PushBack(x)
+			}
+		})
+		newS.fail(s.Err())
+	}()
+	return newS
+}
+
+// ThrottleLast opens a window of d on the first element and emits the most
+// recent element when the window closes.
+func (s *Stream) ThrottleLast(d time.Duration) *Stream {
+	newS := s.derive()
+	go func() {
+		xs := s.channel()
+		var latest interface{}
+		var window <-chan time.Time
+		for {
+			select {
+			case x, ok := <-xs:
+				if !ok {
+					if window != nil {
+						newS.PushBack(latest)
+					}
+					newS.fail(s.Err())
+					return
+				}
+				latest = x
+				if window == nil {
+					window = s.clk.After(d)
+				}
+			case <-window:
+				newS.PushBack(latest)
+				window = nil
+			}
+		}
+	}()
+	return newS
+}
+
+// RateLimit delays elements so that on average at most rate pass per
+// second, allowing bursts of up to burst elements. Nothing is dropped.
+func (s *Stream) RateLimit(rate float64, burst uint) *Stream {
+	if rate <= 0 || burst == 0 {
+		panic("[ERROR] rate and burst must be positive")
+	}
+	newS := s.derive()
+	go func() {
+		tokens := float64(burst)
+		last := s.clk.Now()
+		s.forEach(func(x interface{}) {
+			now := s.clk.Now()
+			tokens = math.Min(float64(burst), tokens+now.Sub(last).Seconds()*rate)
+			last = now
+			if tokens < 1 {
+				wait := time.Duration((1 - tokens) / rate * float64(time.Second))
+				s.clk.Sleep(wait)
+				last = last.Add(wait)
+				tokens = 1
+			}
+			tokens--
+			newS.PushBack(x)
+		})
+		newS.fail(s.Err())
+	}()
+	return newS
+}
@@ -0,0 +1,223 @@
+package stream
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/nl253/DataStructures/clock"
+	"github.com/nl253/DataStructures/list"
+)
+
+var epoch = time.Unix(0, 0)
+
+// settle waits until the operator reading s has taken everything pushed so
+// far and is back waiting for more, so that a following Advance is observed
+// after the pushed elements.
+func settle(s *Stream) {
+	for {
+		s.bufLk.Lock()
+		done := s.closed || (s.buf.Empty() && !s.lks.Empty())
+		s.bufLk.Unlock()
+		if done {
+			return
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestStream_Debounce(t *testing.T) {
+	should := fStream("Debounce", t)
+	should("emit only after a quiet period", list.New(2, 3), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New().WithClock(v)
+		out := s.Debounce(time.Second)
+		s.PushBack(1)
+		v.BlockUntilCreated(1)
+		v.Advance(500 * time.Millisecond)
+		s.PushBack(2)
+		v.BlockUntilCreated(2)
+		v.Advance(time.Second)
+		fst := out.Pull()
+		s.PushBack(3)
+		s.Close()
+		return list.New(fst, out.Pull())
+	})
+	should("inherit the clock", true, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		return New().WithClock(v).Debounce(time.Second).Clock() == v
+	})
+}
+
+func TestStream_Sample(t *testing.T) {
+	should := fStream("Sample", t)
+	should("emit the latest element per tick", list.New(2, 3), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New().WithClock(v)
+		out := s.Sample(time.Second)
+		v.BlockUntil(1)
+		s.PushBack(1)
+		s.PushBack(2)
+		settle(s)
+		v.Advance(time.Second)
+		fst := out.Pull()
+		v.BlockUntilCreated(2)
+		v.Advance(time.Second)
+		s.PushBack(3)
+		settle(s)
+		v.BlockUntilCreated(3)
+		v.Advance(time.Second)
+		snd := out.Pull()
+		s.Close()
+		return list.New(fst, snd)
+	})
+	should("flush the pending element when the stream ends", list.New(2), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		return New(1, 2).Close().WithClock(v).Sample(time.Hour).PullAll()
+	})
+}
+
+func TestStream_ThrottleFirst(t *testing.T) {
+	should := fStream("ThrottleFirst", t)
+	should("drop elements within the window", list.New(1, 3), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New().WithClock(v)
+		out := s.ThrottleFirst(time.Second)
+		s.PushBack(1)
+		fst := out.Pull()
+		v.Advance(500 * time.Millisecond)
+		s.PushBack(2)
+		settle(s)
+		v.Advance(500 * time.Millisecond)
+		s.PushBack(3)
+		s.Close()
+		return list.New(fst, out.Pull())
+	})
+}
+
+func TestStream_ThrottleLast(t *testing.T) {
+	should := fStream("ThrottleLast", t)
+	should("emit the last element of each window", list.New(2, 3), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New().WithClock(v)
+		out := s.ThrottleLast(time.Second)
+		s.PushBack(1)
+		s.PushBack(2)
+		settle(s)
+		v.Advance(time.Second)
+		fst := out.Pull()
+		s.PushBack(3)
+		s.Close()
+		return list.New(fst, out.Pull())
+	})
+}
+
+func TestStream_RateLimit(t *testing.T) {
+	should := fStream("RateLimit", t)
+	should("let a burst through then wait for tokens", []interface{}{epoch, epoch, epoch.Add(500 * time.Millisecond)}, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		out := New(1, 2, 3).Close().WithClock(v).RateLimit(2, 2)
+		at := make([]interface{}, 0)
+		out.Pull()
+		at = append(at, v.Now())
+		out.Pull()
+		at = append(at, v.Now())
+		v.BlockUntil(1)
+		v.Advance(500 * time.Millisecond)
+		out.Pull()
+		return append(at, v.Now())
+	})
+	should("pass all elements", uint(10), func() interface{} {
+		return Range(0, 10, 1).RateLimit(1000, 10).Count()
+	})
+}
+
+func TestStream_Throttle(t *testing.T) {
+	should := fStream("Throttle", t)
+	should("run a 10 second pipeline on a virtual clock", []interface{}{Range(0, 10, 1).PullAll(), epoch.Add(10 * time.Second)}, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		out := Range(0, 10, 1).WithClock(v).Throttle(time.Second).Map(func(x interface{}) interface{} { return x })
+		go func() {
+			for i := uint64(1); i <= 10; i++ {
+				v.BlockUntilCreated(i)
+				v.Advance(time.Second)
+			}
+		}()
+		return []interface{}{out.PullAll(), v.Now()}
+	})
+}
+
+func TestStream_Tick(t *testing.T) {
+	should := fStream("Tick", t)
+	should("emit n times on the default clock", list.New("x", "x", "x"), func() interface{} {
+		return Tick(time.Millisecond, 3, "x").PullAll()
+	})
+}
+
+func TestStream_TickWithClock(t *testing.T) {
+	should := fStream("TickWithClock", t)
+	should("emit on the given clock", list.New("x", "x", "x"), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		out := TickWithClock(v, time.Minute, 3, "x")
+		go func() {
+			for i := uint64(1); i <= 3; i++ {
+				v.BlockUntilCreated(i)
+				v.Advance(time.Minute)
+			}
+		}()
+		return out.PullAll()
+	})
+	should("hand the clock to the returned stream", true, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		return TickWithClock(v, time.Minute, 0, "x").Clock() == v
+	})
+}
+
+func TestStream_Emit(t *testing.T) {
+	should := fStream("Emit", t)
+	should("emit f(n) on the default clock", list.New(0, 2), func() interface{} {
+		return Emit(time.Millisecond, 2, func(n uint) interface{} { return int(n) * 2 }).PullAll()
+	})
+}
+
+func TestStream_EmitWithClock(t *testing.T) {
+	should := fStream("EmitWithClock", t)
+	should("emit f(n) at the given frequency", []interface{}{0, epoch.Add(time.Hour), 2, epoch.Add(2 * time.Hour)}, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		out := EmitWithClock(v, time.Hour, 2, func(n uint) interface{} { return int(n) * 2 })
+		v.BlockUntil(1)
+		v.Advance(time.Hour)
+		fst := out.Pull()
+		at1 := v.Now()
+		v.BlockUntil(1)
+		v.Advance(time.Hour)
+		return []interface{}{fst, at1, out.Pull(), v.Now()}
+	})
+}
+
+func TestStream_Spike(t *testing.T) {
+	should := fStream("Spike", t)
+	should("release n elements per period without losing any", list.New(0, 1, 2, 3, 4), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		out := Range(0, 5, 1).WithClock(v).Spike(2, time.Second)
+		go func() {
+			for i := uint64(1); i <= 3; i++ {
+				v.BlockUntilCreated(i)
+				v.Advance(time.Second)
+			}
+		}()
+		return out.PullAll()
+	})
+}
+
+func TestStream_Delay(t *testing.T) {
+	should := fStream("Delay", t)
+	should("hold elements back until d has passed", []interface{}{0, true}, func() interface{} {
+		v := clock.NewVirtual(epoch)
+		out := Range(0, 3, 1).WithClock(v).Delay(time.Hour)
+		v.BlockUntil(1)
+		pending := out.BufSize() == 0
+		v.Advance(time.Hour)
+		return []interface{}{out.Pull(), pending}
+	})
+}
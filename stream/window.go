@@ -0,0 +1,175 @@
+package stream
+
+import (
+	"time"
+
+	"github.com/nl253/DataStructures/clock"
+)
+
+// Window groups consecutive elements into []interface{} batches of n, the
+// last batch holds whatever is left when the stream ends.
+func (s *Stream) Window(n uint) *Stream {
+	return s.SlidingWindow(n, n)
+}
+
+// SlidingWindow emits the last n elements every step elements. Only full
+// windows are emitted except for a tumbling window (step == n) which also
+// flushes the remainder. A step larger than n skips elements between windows.
+func (s *Stream) SlidingWindow(n uint, step uint) *Stream {
+	if n == 0 || step == 0 {
+		panic("[ERROR] window size and step must be positive")
+	}
+	newS := s.derive()
+	go func() {
+		buf := make([]interface{}, 0, n)
+		skip := uint(0)
+		s.forEach(func(x interface{}) {
+			if skip > 0 {
+				skip--
+				return
+			}
+			buf = append(buf, x)
+			if uint(len(buf)) < n {
+				return
+			}
+			newS.PushBack(copyWindow(buf))
+			if step >= n {
+				skip = step - n
+				buf = buf[:0]
+			} else {
+				buf = append(buf[:0], buf[step:]...)
+			}
+		})
+		if step == n && len(buf) > 0 {
+			newS.PushBack(copyWindow(buf))
+		}
+		newS.fail(s.Err())
+	}()
+	return newS
+}
+
+// TumblingTime emits the elements that arrived during each consecutive
+// period d. Periods in which nothing arrived are not emitted.
+func (s *Stream) TumblingTime(d time.Duration) *Stream {
+	return s.SlidingTime(d, d)
+}
+
+// SlidingTime emits, every slide, the elements that arrived during the last
+// d. Empty windows are not emitted.
+func (s *Stream) SlidingTime(d time.Duration, slide time.Duration) *Stream {
+	if d <= 0 || slide <= 0 {
+		panic("[ERROR] window duration and slide must be positive")
+	}
+	type stamped struct {
+		at time.Time
+		x  interface{}
+	}
+	newS := s.derive()
+	go func() {
+		xs := s.channel()
+		buf := make([]stamped, 0)
+		flush := func(now time.Time) {
+			for len(buf) > 0 && buf[0].at.Before(now.Add(-d)) {
+				buf = buf[1:]
+			}
+			if len(buf) > 0 {
+				window := make([]interface{}, len(buf))
+				for i, st := range buf {
+					window[i] = st.x
+				}
+				newS.PushBack(window)
+			}
+		}
+		tick := s.clk.After(slide)
+		for {
+			select {
+			case x, ok := <-xs:
+				if !ok {
+					flush(s.clk.Now())
+					newS.fail(s.Err())
+					return
+				}
+				buf = append(buf, stamped{s.clk.Now(), x})
+			case now := <-tick:
+				flush(now)
+				if slide >= d {
+					buf = buf[:0]
+				}
+				tick = s.clk.After(slide)
+			}
+		}
+	}()
+	return newS
+}
+
+// SessionWindow groups elements separated by less than gap, a window is
+// emitted once nothing arrives for gap or the stream ends.
+func (s *Stream) SessionWindow(gap time.Duration) *Stream {
+	newS := s.derive()
+	go func() {
+		xs := s.channel()
+		buf := make([]interface{}, 0)
+		var timer clock.Timer
+		var timeout <-chan time.Time
+		for {
+			select {
+			case x, ok := <-xs:
+				if timer != nil {
+					timer.Stop()
+				}
+				if !ok {
+					if len(buf) > 0 {
+						newS.PushBack(buf)
+					}
+					newS.fail(s.Err())
+					return
+				}
+				buf = append(buf, x)
+				timer = s.clk.NewTimer(gap)
+				timeout = timer.Chan()
+			case <-timeout:
+				newS.PushBack(buf)
+				buf = make([]interface{}, 0)
+				timer, timeout = nil, nil
+			}
+		}
+	}()
+	return newS
+}
+
+// SubStreams turns each []interface{} window into a closed *Stream so
+// per-window aggregates can use Reduce, Sum, Count etc.
+func (s *Stream) SubStreams() *Stream {
+	return s.Map(func(x interface{}) interface{} { return New(x.([]interface{})...).Close() })
+}
+
+func copyWindow(xs []interface{}) []interface{} {
+	window := make([]interface{}, len(xs))
+	copy(window, xs)
+	return window
+}
+
+// channel pumps s into a Go channel so it can take part in a select, the
+// channel is closed when s ends.
+func (s *Stream) channel() <-chan interface{} {
+	return s.channelUntil(nil)
+}
+
+// channelUntil is channel that also stops pumping once done is closed, so
+// operators giving up early do not leave the pump blocked on a send. The
+// element it was holding is put back into s.
+func (s *Stream) channelUntil(done <-chan struct{}) <-chan interface{} {
+	c := make(chan interface{})
+	go func() {
+		defer close(c)
+		for x := s.Pull(); x != EndMarker; x = s.Pull() {
+			select {
+			case c <- x:
+			case <-done:
+				s.PushFront(x)
+				return
+			}
+		}
+	}()
+	return c
+}
@@ -0,0 +1,86 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nl253/DataStructures/clock"
+	"github.com/nl253/DataStructures/list"
+)
+
+func TestStream_Window(t *testing.T) {
+	should := fStream("Window", t)
+	should("batch n elements at a time", list.New([]interface{}{0, 1}, []interface{}{2, 3}, []interface{}{4}).String(), func() interface{} {
+		return Range(0, 5, 1).Window(2).PullAll().String()
+	})
+	should("emit nothing for an empty stream", list.New(), func() interface{} { return Range(0, 0, 1).Window(2).PullAll() })
+	should("work with Sum per window", list.New(1.0, 5.0, 4.0), func() interface{} {
+		return Range(0, 5, 1).Window(2).SubStreams().Map(func(w interface{}) interface{} { return w.(*Stream).Sum() }).PullAll()
+	})
+}
+
+func TestStream_SlidingWindow(t *testing.T) {
+	should := fStream("SlidingWindow", t)
+	should("overlap windows when step < n", list.New([]interface{}{0, 1, 2}, []interface{}{1, 2, 3}, []interface{}{2, 3, 4}).String(), func() interface{} {
+		return Range(0, 5, 1).SlidingWindow(3, 1).PullAll().String()
+	})
+	should("skip elements when step > n", list.New([]interface{}{0, 1}, []interface{}{3, 4}).String(), func() interface{} {
+		return Range(0, 6, 1).SlidingWindow(2, 3).PullAll().String()
+	})
+	should("generate valid stream", true, func() interface{} { return isValid(Range(0, 5, 1).SlidingWindow(2, 1)) })
+}
+
+func TestStream_TumblingTime(t *testing.T) {
+	should := fStream("TumblingTime", t)
+	should("batch elements by arrival period", list.New([]interface{}{1, 2}, []interface{}{3}).String(), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New(1, 2).WithClock(v)
+		out := s.TumblingTime(time.Second)
+		settle(s)
+		v.BlockUntil(1)
+		v.Advance(time.Second)
+		fst := out.Pull()
+		v.Advance(500 * time.Millisecond)
+		s.PushBack(3)
+		s.Close()
+		return list.New(fst, out.Pull()).String()
+	})
+}
+
+func TestStream_SlidingTime(t *testing.T) {
+	should := fStream("SlidingTime", t)
+	should("repeat elements in overlapping windows", list.New([]interface{}{1}, []interface{}{1, 2}, []interface{}{2}).String(), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New(1).WithClock(v)
+		out := s.SlidingTime(2*time.Second, time.Second)
+		settle(s)
+		v.BlockUntil(1)
+		v.Advance(time.Second)
+		fst := out.Pull()
+		s.PushBack(2)
+		settle(s)
+		v.BlockUntilCreated(2)
+		v.Advance(time.Second)
+		snd := out.Pull()
+		v.BlockUntilCreated(3)
+		v.Advance(time.Second)
+		trd := out.Pull()
+		s.Close()
+		return list.New(fst, snd, trd).String()
+	})
+}
+
+func TestStream_SessionWindow(t *testing.T) {
+	should := fStream("SessionWindow", t)
+	should("split on gaps in activity", list.New([]interface{}{1, 2}, []interface{}{3}).String(), func() interface{} {
+		v := clock.NewVirtual(epoch)
+		s := New(1, 2).WithClock(v)
+		out := s.SessionWindow(time.Second)
+		v.BlockUntilCreated(2)
+		v.Advance(time.Second)
+		fst := out.Pull()
+		s.PushBack(3)
+		s.Close()
+		return list.New(fst, out.Pull()).String()
+	})
+}
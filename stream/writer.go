@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+// Encoder writes a single element to w and reports how many bytes it wrote.
+type Encoder func(w io.Writer, x interface{}) (int, error)
+
+// Raw writes bytes, byte slices, runes and strings as they are and anything
+// else the way fmt.Print would, so it is the inverse of FromFile and
+// FromReaderRunes.
+func Raw(w io.Writer, x interface{}) (int, error) {
+	switch x.(type) {
+	case byte:
+		return w.Write([]byte{x.(byte)})
+	case []byte:
+		return w.Write(x.([]byte))
+	case rune:
+		return io.WriteString(w, string(x.(rune)))
+	case string:
+		return io.WriteString(w, x.(string))
+	default:
+		return fmt.Fprint(w, x)
+	}
+}
+
+// Line is Raw followed by a newline.
+func Line(w io.Writer, x interface{}) (int, error) {
+	n, err := Raw(w, x)
+	if err != nil {
+		return n, err
+	}
+	m, err := io.WriteString(w, "\n")
+	return n + m, err
+}
+
+func Format(format string) Encoder {
+	return func(w io.Writer, x interface{}) (int, error) { return fmt.Fprintf(w, format, x) }
+}
+
+// ToWriter drains s into w through a buffer and flushes it once s ends. It
+// stops at the first failed write, leaving the rest of s unread. Otherwise
+// the error is the one that ended the source, see Err. The count is of
+// bytes that actually reached w.
+func (s *Stream) ToWriter(w io.Writer, enc Encoder) (int64, error) {
+	bw := bufio.NewWriter(w)
+	written := int64(0)
+	for x := s.Pull(); x != EndMarker; x = s.Pull() {
+		n, err := enc(bw, x)
+		written += int64(n)
+		if err != nil {
+			return written - int64(bw.Buffered()), err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return written - int64(bw.Buffered()), err
+	}
+	return written, s.Err()
+}
+
+func (s *Stream) ToLines(w io.Writer) (int64, error) {
+	return s.ToWriter(w, Line)
+}
+
+// ToFile creates (or truncates) filePath and writes s to it with Raw,
+// compressing by extension, see CompressionOf.
+func (s *Stream) ToFile(filePath string) (int64, error) {
+	return s.ToFileCompressed(filePath, AutoCompression)
+}
+
+// ToList is PullAll that also reports why the source ended, see Err.
+func (s *Stream) ToList() (*list.ConcurrentList, error) {
+	xs := s.PullAll()
+	return xs, s.Err()
+}
+
+func (s *Stream) Concat() string {
+	return s.Join("")
+}
+
+func (s *Stream) Join(delim string) string {
+	var builder strings.Builder
+	first := true
+	s.forEach(func(x interface{}) {
+		if !first {
+			builder.WriteString(delim)
+		}
+		first = false
+		_, _ = Raw(&builder, x)
+	})
+	return builder.String()
+}
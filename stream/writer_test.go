@@ -0,0 +1,95 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/nl253/DataStructures/list"
+)
+
+// limitWriter accepts n bytes and fails every write after that.
+type limitWriter struct {
+	n   int
+	buf bytes.Buffer
+}
+
+var errFull = errors.New("full")
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		m, _ := w.buf.Write(p[:w.n])
+		w.n = 0
+		return m, errFull
+	}
+	w.n -= len(p)
+	return w.buf.Write(p)
+}
+
+func TestStream_ToWriter(t *testing.T) {
+	should := fStream("ToWriter", t)
+	should("write every elem and count bytes", []interface{}{"1,2,3,", int64(6), nil}, func() interface{} {
+		var buf bytes.Buffer
+		n, err := Ints(1, 4).ToWriter(&buf, Format("%d,"))
+		return []interface{}{buf.String(), n, err}
+	})
+	should("count only bytes that reached the writer", []interface{}{int64(5000), errFull}, func() interface{} {
+		w := &limitWriter{n: 5000}
+		n, err := FromStr(strings.Repeat("x", 10000)).ToWriter(w, Raw)
+		return []interface{}{n, err}
+	})
+	should("report the error that ended the source", []interface{}{"a", iotest.ErrTimeout}, func() interface{} {
+		var buf bytes.Buffer
+		_, err := FromReader(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("ab")))).ToWriter(&buf, Raw)
+		return []interface{}{buf.String(), err}
+	})
+}
+
+func TestStream_ToLines(t *testing.T) {
+	should := fStream("ToLines", t)
+	should("write one elem per line", "a\n1\n", func() interface{} {
+		var buf bytes.Buffer
+		_, _ = New("a", 1).Close().ToLines(&buf)
+		return buf.String()
+	})
+}
+
+func TestStream_ToFile(t *testing.T) {
+	should := fStream("ToFile", t)
+	should("round trip FromFile", "zółw\n", func() interface{} {
+		path := filepath.Join(t.TempDir(), "out.txt")
+		if _, err := FromStr("zółw\n").ToFile(path); err != nil {
+			return err
+		}
+		copied := filepath.Join(t.TempDir(), "copy.txt")
+		if _, err := FromFile(path).ToFile(copied); err != nil {
+			return err
+		}
+		content, _ := ioutil.ReadFile(copied)
+		return string(content)
+	})
+	should("fail for unwritable paths", true, func() interface{} {
+		_, err := New().Close().ToFile(filepath.Join(t.TempDir(), "missing", "out.txt"))
+		return err != nil
+	})
+}
+
+func TestStream_ToList(t *testing.T) {
+	should := fStream("ToList", t)
+	should("collect elems", []interface{}{list.New(1, 2), nil}, func() interface{} {
+		xs, err := Ints(1, 3).ToList()
+		return []interface{}{xs, err}
+	})
+}
+
+func TestStream_Join(t *testing.T) {
+	should := fStream("Join", t)
+	should("put delim only between elems", "a, b, c", func() interface{} { return New("a", "b", "c").Close().Join(", ") })
+	should("join a single elem", "a", func() interface{} { return New("a").Close().Join(", ") })
+	should("join nothing", "", func() interface{} { return New().Close().Join(", ") })
+	should("join non-strings", "1-2", func() interface{} { return Ints(1, 3).Join("-") })
+}
@@ -0,0 +1,97 @@
+package unionfind
+
+import (
+	"sync"
+
+	"github.com/nl253/DataStructures/list"
+	"github.com/nl253/DataStructures/stream"
+)
+
+// ConcurrentUnionFind guards a UnionFind with a single mutex. Find compresses
+// paths so even lookups need the exclusive lock.
+type ConcurrentUnionFind struct {
+	uf *UnionFind
+	lk *sync.Mutex
+}
+
+func NewConcurrent(xs ...interface{}) *ConcurrentUnionFind {
+	return &ConcurrentUnionFind{uf: New(xs...), lk: &sync.Mutex{}}
+}
+
+func (c *ConcurrentUnionFind) Add(x interface{}) bool {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.Add(x)
+}
+
+func (c *ConcurrentUnionFind) Contains(x interface{}) bool {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.Contains(x)
+}
+
+func (c *ConcurrentUnionFind) Find(x interface{}) (interface{}, bool) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.Find(x)
+}
+
+func (c *ConcurrentUnionFind) Union(x interface{}, y interface{}) bool {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.Union(x, y)
+}
+
+func (c *ConcurrentUnionFind) UnionStream(s *stream.Stream, f func(x interface{}) (interface{}, interface{})) *ConcurrentUnionFind {
+	s.Reduce(c, func(acc interface{}, x interface{}) interface{} {
+		a, b := f(x)
+		acc.(*ConcurrentUnionFind).Union(a, b)
+		return acc
+	})
+	return c
+}
+
+func (c *ConcurrentUnionFind) Connected(x interface{}, y interface{}) bool {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.Connected(x, y)
+}
+
+func (c *ConcurrentUnionFind) ComponentCount() uint {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.ComponentCount()
+}
+
+func (c *ConcurrentUnionFind) ComponentSize(x interface{}) uint {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.ComponentSize(x)
+}
+
+func (c *ConcurrentUnionFind) Size() uint {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.Size()
+}
+
+func (c *ConcurrentUnionFind) Components() *list.ConcurrentList {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.Components()
+}
+
+func (c *ConcurrentUnionFind) Eq(x interface{}) bool {
+	switch x.(type) {
+	case *ConcurrentUnionFind:
+		return c == x.(*ConcurrentUnionFind)
+	default:
+		return false
+	}
+}
+
+func (c *ConcurrentUnionFind) String() string {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.String()
+}
@@ -0,0 +1,37 @@
+package unionfind
+
+import (
+	"sync"
+	"testing"
+
+	ut "github.com/nl253/Testing"
+)
+
+var fCUF = ut.Test("ConcurrentUnionFind")
+
+func TestConcurrentUnionFind_IsThreadSafe(t *testing.T) {
+	should := fCUF("general concurrency", t)
+	should("end with one set after concurrent unions", uint(1), func() interface{} {
+		uf := NewConcurrent()
+		wg := sync.WaitGroup{}
+		for i := 1; i < MANY; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				uf.Union(i-1, i)
+				uf.Connected(0, i)
+			}(i)
+		}
+		wg.Wait()
+		return uf.ComponentCount()
+	})
+}
+
+func TestConcurrentUnionFind_Components(t *testing.T) {
+	should := fCUF("Components", t)
+	should("group elements", "[[1 2] [3]]", func() interface{} {
+		uf := NewConcurrent(1, 2, 3)
+		uf.Union(2, 1)
+		return uf.Components().String()
+	})
+}
@@ -0,0 +1,124 @@
+package unionfind
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func init() {
+	codec.Register("unionfind.UnionFind", &UnionFind{})
+	codec.Register("unionfind.ConcurrentUnionFind", &ConcurrentUnionFind{})
+}
+
+// snapshot lists elements in insertion order next to the position of the
+// root of their set.
+type snapshot struct {
+	Elems []interface{}
+	Roots []int
+}
+
+type jsonSnapshot struct {
+	Elems []codec.Value `json:"elems"`
+	Roots []int         `json:"roots"`
+}
+
+func (uf *UnionFind) snapshot() snapshot {
+	idx := make(map[interface{}]int, len(uf.order))
+	for i, x := range uf.order {
+		idx[x] = i
+	}
+	roots := make([]int, len(uf.order))
+	for i, x := range uf.order {
+		roots[i] = idx[uf.find(x)]
+	}
+	elems := make([]interface{}, len(uf.order))
+	copy(elems, uf.order)
+	return snapshot{Elems: elems, Roots: roots}
+}
+
+func (uf *UnionFind) restore(s snapshot) error {
+	if len(s.Roots) != len(s.Elems) {
+		return codec.ErrInvalidData
+	}
+	newUF := New(s.Elems...)
+	for i, x := range s.Elems {
+		if s.Roots[i] < 0 || s.Roots[i] >= len(s.Elems) {
+			return codec.ErrInvalidData
+		}
+		newUF.Union(x, s.Elems[s.Roots[i]])
+	}
+	*uf = *newUF
+	return nil
+}
+
+// MarshalBinary gob-encodes the sets, element types must be registered with
+// codec.Register.
+func (uf *UnionFind) MarshalBinary() ([]byte, error) {
+	return codec.MarshalBinary(uf.snapshot())
+}
+
+func (uf *UnionFind) UnmarshalBinary(data []byte) error {
+	var s snapshot
+	if err := codec.UnmarshalBinary(data, &s); err != nil {
+		return err
+	}
+	return uf.restore(s)
+}
+
+func (uf *UnionFind) MarshalJSON() ([]byte, error) {
+	s := uf.snapshot()
+	vs, err := codec.WrapAll(s.Elems)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonSnapshot{Elems: vs, Roots: s.Roots})
+}
+
+func (uf *UnionFind) UnmarshalJSON(data []byte) error {
+	var s jsonSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	elems, err := codec.UnwrapAll(s.Elems)
+	if err != nil {
+		return err
+	}
+	return uf.restore(snapshot{Elems: elems, Roots: s.Roots})
+}
+
+func (c *ConcurrentUnionFind) MarshalBinary() ([]byte, error) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.MarshalBinary()
+}
+
+func (c *ConcurrentUnionFind) UnmarshalBinary(data []byte) error {
+	return c.decode(func(uf *UnionFind) error { return uf.UnmarshalBinary(data) })
+}
+
+func (c *ConcurrentUnionFind) MarshalJSON() ([]byte, error) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.uf.MarshalJSON()
+}
+
+func (c *ConcurrentUnionFind) UnmarshalJSON(data []byte) error {
+	return c.decode(func(uf *UnionFind) error { return uf.UnmarshalJSON(data) })
+}
+
+// decode fills a fresh UnionFind and swaps it in, c may be the zero value.
+func (c *ConcurrentUnionFind) decode(f func(uf *UnionFind) error) error {
+	uf := New()
+	if err := f(uf); err != nil {
+		return err
+	}
+	if c.lk == nil {
+		c.lk = &sync.Mutex{}
+	}
+	c.lk.Lock()
+	c.uf = uf
+	c.lk.Unlock()
+	return nil
+}
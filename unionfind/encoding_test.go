@@ -0,0 +1,68 @@
+package unionfind
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/nl253/DataStructures/codec"
+)
+
+func sample() *UnionFind {
+	uf := New("a", "b", "c", 1, 2)
+	uf.Union("a", "c")
+	uf.Union(2, 1)
+	return uf
+}
+
+func TestUnionFind_MarshalBinary(t *testing.T) {
+	should := fUF("MarshalBinary", t)
+	should("round trip through gob", sample().String(), func() interface{} {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(sample()); err != nil {
+			return err
+		}
+		var uf UnionFind
+		if err := gob.NewDecoder(&buf).Decode(&uf); err != nil {
+			return err
+		}
+		return uf.String()
+	})
+	should("refuse roots out of range", codec.ErrInvalidData, func() interface{} {
+		data, _ := codec.MarshalBinary(snapshot{Elems: []interface{}{1}, Roots: []int{3}})
+		return New().UnmarshalBinary(data)
+	})
+}
+
+func TestUnionFind_MarshalJSON(t *testing.T) {
+	should := fUF("MarshalJSON", t)
+	should("round trip keeping element types", []interface{}{sample().String(), true}, func() interface{} {
+		data, err := json.Marshal(sample())
+		if err != nil {
+			return err
+		}
+		uf := New()
+		if err := json.Unmarshal(data, uf); err != nil {
+			return err
+		}
+		return []interface{}{uf.String(), uf.Connected(1, 2)}
+	})
+}
+
+func TestConcurrentUnionFind_MarshalJSON(t *testing.T) {
+	should := fCUF("MarshalJSON", t)
+	should("round trip into the zero value", "[[1 2] [3]]", func() interface{} {
+		uf := NewConcurrent(1, 2, 3)
+		uf.Union(2, 1)
+		data, err := json.Marshal(uf)
+		if err != nil {
+			return err
+		}
+		var decoded ConcurrentUnionFind
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+		return decoded.Components().String()
+	})
+}
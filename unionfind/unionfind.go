@@ -0,0 +1,164 @@
+package unionfind
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nl253/DataStructures/list"
+	"github.com/nl253/DataStructures/stream"
+)
+
+// UnionFind is a disjoint-set forest with path compression and union by
+// rank. It is not safe for concurrent use, see ConcurrentUnionFind.
+type UnionFind struct {
+	parent map[interface{}]interface{}
+	rank   map[interface{}]uint
+	size   map[interface{}]uint
+	order  []interface{}
+	count  uint
+}
+
+func New(xs ...interface{}) *UnionFind {
+	uf := &UnionFind{
+		parent: make(map[interface{}]interface{}),
+		rank:   make(map[interface{}]uint),
+		size:   make(map[interface{}]uint),
+		order:  make([]interface{}, 0),
+	}
+	for _, x := range xs {
+		uf.Add(x)
+	}
+	return uf
+}
+
+// Add puts x in a singleton set unless it is already known.
+func (uf *UnionFind) Add(x interface{}) bool {
+	if _, ok := uf.parent[x]; ok {
+		return false
+	}
+	uf.parent[x] = x
+	uf.size[x] = 1
+	uf.order = append(uf.order, x)
+	uf.count++
+	return true
+}
+
+func (uf *UnionFind) Contains(x interface{}) bool {
+	_, ok := uf.parent[x]
+	return ok
+}
+
+func (uf *UnionFind) Find(x interface{}) (interface{}, bool) {
+	if !uf.Contains(x) {
+		return nil, false
+	}
+	return uf.find(x), true
+}
+
+func (uf *UnionFind) find(x interface{}) interface{} {
+	root := x
+	for p := uf.parent[root]; p != root; p = uf.parent[root] {
+		root = p
+	}
+	for x != root {
+		next := uf.parent[x]
+		uf.parent[x] = root
+		x = next
+	}
+	return root
+}
+
+// Union merges the sets of x and y, adding either if missing, and reports
+// whether they were in different sets.
+func (uf *UnionFind) Union(x interface{}, y interface{}) bool {
+	uf.Add(x)
+	uf.Add(y)
+	rx, ry := uf.find(x), uf.find(y)
+	if rx == ry {
+		return false
+	}
+	if uf.rank[rx] < uf.rank[ry] {
+		rx, ry = ry, rx
+	}
+	if uf.rank[rx] == uf.rank[ry] {
+		uf.rank[rx]++
+	}
+	uf.parent[ry] = rx
+	uf.size[rx] += uf.size[ry]
+	delete(uf.size, ry)
+	delete(uf.rank, ry)
+	uf.count--
+	return true
+}
+
+// UnionStream consumes s, unioning the pair of keys f extracts from each
+// element, and returns uf once s ends.
+func (uf *UnionFind) UnionStream(s *stream.Stream, f func(x interface{}) (interface{}, interface{})) *UnionFind {
+	s.Reduce(uf, func(acc interface{}, x interface{}) interface{} {
+		a, b := f(x)
+		acc.(*UnionFind).Union(a, b)
+		return acc
+	})
+	return uf
+}
+
+func (uf *UnionFind) Connected(x interface{}, y interface{}) bool {
+	if !uf.Contains(x) || !uf.Contains(y) {
+		return false
+	}
+	return uf.find(x) == uf.find(y)
+}
+
+func (uf *UnionFind) ComponentCount() uint { return uf.count }
+
+func (uf *UnionFind) ComponentSize(x interface{}) uint {
+	if !uf.Contains(x) {
+		return 0
+	}
+	return uf.size[uf.find(x)]
+}
+
+func (uf *UnionFind) Size() uint { return uint(len(uf.order)) }
+
+// Components lists every set as a ConcurrentList. Sets and their members
+// are ordered by when their elements were first added.
+func (uf *UnionFind) Components() *list.ConcurrentList {
+	groups := make(map[interface{}]*list.ConcurrentList, uf.count)
+	components := list.New()
+	for _, x := range uf.order {
+		root := uf.find(x)
+		group, ok := groups[root]
+		if !ok {
+			group = list.New()
+			groups[root] = group
+			components.PushBack(group)
+		}
+		group.PushBack(x)
+	}
+	return components
+}
+
+func (uf *UnionFind) Clone() *UnionFind {
+	newUF := New(uf.order...)
+	for _, x := range uf.order {
+		newUF.Union(x, uf.find(x))
+	}
+	return newUF
+}
+
+func (uf *UnionFind) Eq(x interface{}) bool {
+	switch x.(type) {
+	case *UnionFind:
+		return uf == x.(*UnionFind)
+	default:
+		return false
+	}
+}
+
+func (uf *UnionFind) String() string {
+	parts := make([]string, 0, uf.count)
+	uf.Components().ForEach(func(group interface{}, _ uint) {
+		parts = append(parts, group.(*list.ConcurrentList).String())
+	})
+	return fmt.Sprintf("{%s}", strings.Join(parts, " "))
+}
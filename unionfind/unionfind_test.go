@@ -0,0 +1,95 @@
+package unionfind
+
+import (
+	"testing"
+
+	"github.com/nl253/DataStructures/stream"
+	ut "github.com/nl253/Testing"
+)
+
+const MANY int = 1000
+
+var fUF = ut.Test("UnionFind")
+
+func TestUnionFind_Union(t *testing.T) {
+	should := fUF("Union", t)
+	should("merge sets and report it", []bool{true, true, false}, func() interface{} {
+		uf := New()
+		return []bool{uf.Union(1, 2), uf.Union(2, 3), uf.Union(1, 3)}
+	})
+	should("add missing elements", uint(2), func() interface{} {
+		uf := New()
+		uf.Union("a", "b")
+		return uf.Size()
+	})
+	should("keep trees shallow", true, func() interface{} {
+		uf := New()
+		for i := 1; i < MANY; i++ {
+			uf.Union(i-1, i)
+		}
+		uf.Find(0)
+		return uf.rank[uf.find(0)] <= 10 && uf.parent[0] == uf.find(0)
+	})
+}
+
+func TestUnionFind_Find(t *testing.T) {
+	should := fUF("Find", t)
+	should("not find missing elements", false, func() interface{} {
+		_, ok := New(1).Find(2)
+		return ok
+	})
+	should("find the same root for connected elements", true, func() interface{} {
+		uf := New()
+		uf.Union(1, 2)
+		r1, _ := uf.Find(1)
+		r2, _ := uf.Find(2)
+		return r1 == r2
+	})
+}
+
+func TestUnionFind_Connected(t *testing.T) {
+	should := fUF("Connected", t)
+	uf := New(1, 2, 3, 4)
+	uf.Union(1, 2)
+	uf.Union(3, 4)
+	should("connect unioned elements", true, func() interface{} { return uf.Connected(1, 2) })
+	should("not connect separate sets", false, func() interface{} { return uf.Connected(2, 3) })
+	should("not connect missing elements", false, func() interface{} { return uf.Connected(1, 5) })
+}
+
+func TestUnionFind_ComponentCount(t *testing.T) {
+	should := fUF("ComponentCount", t)
+	should("count singletons", uint(3), func() interface{} { return New(1, 2, 3).ComponentCount() })
+	should("count merged sets", uint(2), func() interface{} {
+		uf := New(1, 2, 3)
+		uf.Union(1, 3)
+		return uf.ComponentCount()
+	})
+	should("track set sizes", uint(3), func() interface{} {
+		uf := New(1, 2, 3, 4)
+		uf.Union(1, 3)
+		uf.Union(4, 3)
+		return uf.ComponentSize(1)
+	})
+}
+
+func TestUnionFind_Components(t *testing.T) {
+	should := fUF("Components", t)
+	should("group elements in insertion order", "[[1 3] [2 4] [5]]", func() interface{} {
+		uf := New(1, 2, 3, 4, 5)
+		uf.Union(1, 3)
+		uf.Union(4, 2)
+		return uf.Components().String()
+	})
+}
+
+func TestUnionFind_UnionStream(t *testing.T) {
+	should := fUF("UnionStream", t)
+	should("cluster pairs from a stream", "{[a b c] [x y]}", func() interface{} {
+		pairs := stream.New([2]string{"a", "b"}, [2]string{"x", "y"}, [2]string{"c", "a"}).Close()
+		return New().UnionStream(pairs, func(x interface{}) (interface{}, interface{}) {
+			pair := x.([2]string)
+			return pair[0], pair[1]
+		}).String()
+	})
+}